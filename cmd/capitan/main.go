@@ -1,12 +1,24 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/chindada/capitan/internal/app/capitan"
 	"github.com/chindada/capitan/internal/config"
 	"github.com/chindada/leopard/pkg/log"
 )
 
 func main() {
+	// capitan config print [flags], the effective config without secrets
+	if len(os.Args) > 2 && os.Args[1] == "config" && os.Args[2] == "print" {
+		if err := config.Print(os.Stdout, os.Args[3:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Init log
 	log.Init()
 
@@ -1,54 +1,183 @@
 package capitan
 
 import (
+	"context"
+	"errors"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 
 	"github.com/chindada/capitan/internal/config"
 	"github.com/chindada/capitan/internal/controller/http/router"
 	"github.com/chindada/capitan/internal/usecases"
-	"github.com/chindada/leopard/pkg/httpserver"
+	"github.com/chindada/capitan/internal/usecases/entity"
 	"github.com/chindada/leopard/pkg/log"
 )
 
+// capitan holds the use cases, those bound to database are rebuilt after restore.
+type capitan struct {
+	logger *log.Log
+	cfg    *config.Config
+	gate   *router.Gate
+	// ctx is the root context of the use cases.
+	ctx context.Context
+
+	stream   usecases.Stream
+	backup   usecases.Backup
+	reloader usecases.Reloader
+
+	// lock guards basic and settings, swapped by build while the probes read them.
+	lock     sync.RWMutex
+	basic    usecases.Basic
+	settings usecases.Settings
+}
+
 func Start() {
 	logger := log.Get()
 	cfg := config.Get()
+	life := newLifecycle(logger)
+	life.OnStop("tracing", cfg.ShutdownTracing)
+	life.OnStop("proxy", blocking(tryStopProxyServer))
+	life.OnStop("database", blocking(cfg.CloseDB))
 
 	// Pre process, do not adjust the order, except for new feature
-	ucSystem := usecases.NewSystem()
-	ucStream := usecases.NewStream()
-	ucBasic := usecases.NewBasic()
-
-	// HTTP Handler
-	r := router.NewRouter(ucSystem).
-		AddV1BasicRoutes(ucBasic).
-		AddV1StreamRoutes(ucStream).
-		AddV1SystemRoutes()
+	a := &capitan{
+		logger:   logger,
+		cfg:      cfg,
+		gate:     router.NewGate(),
+		ctx:      life.Context(),
+		stream:   usecases.NewStream(life.Context()),
+		reloader: usecases.NewReloader(),
+	}
+	a.backup = usecases.NewBackup(life.Context(), a)
+	a.build(a.ctx)
+	a.basic.Start()
+	a.gate.SetProbe(router.NewProbeHandler(usecases.NewHealth(a, a.stream), cfg.Metrics.Token, cfg.Metrics.Allow))
+	life.OnStop("use cases", blocking(a.close))
+	life.OnStop("stream", blocking(a.stream.Close))
+	life.CancelRoot()
 
 	// Start HTTP Server
-	if e := httpserver.New(
-		r.GetHandler(),
-		httpserver.Port(cfg.Server.SRVPort),
-		httpserver.AddLogger(logger),
-	).Start(); e != nil {
+	srv := newServer(logger, a.gate, cfg.Server.SRVPort)
+	if e := srv.Start(); e != nil {
 		logger.Fatalf("API Server error: %s", e)
 	}
+	life.OnStop("http", a.stopHTTP(srv))
 
-	defer func() {
-		cfg.CloseDB()
-		tryStopProxyServer()
-		logger.Info("Shut down")
-	}()
+	a.waitSignal()
+	logger.Infof("Shutting down in %s", cfg.Server.ShutdownTimeout)
+	if err := life.Shutdown(cfg.Server.ShutdownTimeout); err != nil {
+		logger.Warnf("Shut down with errors: %v", err)
+	}
+	logger.Info("Shut down")
+}
 
-	// Waiting signal
+// waitSignal returns on interrupt, SIGHUP reloads the config.
+func (a *capitan) waitSignal() {
 	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
-	<-interrupt
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGHUP)
+	defer signal.Stop(interrupt)
+	for sig := range interrupt {
+		if sig != syscall.SIGHUP {
+			return
+		}
+		if _, err := a.reloader.ReloadConfig(context.Background()); err != nil {
+			a.logger.Errorf("Reload config failed: %v", err)
+		}
+	}
+}
+
+// stopHTTP stops accepting and closes the websockets at once, the websockets are hijacked and not waited by the server.
+func (a *capitan) stopHTTP(srv *server) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		gateDone := make(chan error, 1)
+		go func() {
+			gateDone <- a.gate.Shutdown(ctx)
+		}()
+		return errors.Join(srv.Shutdown(ctx), <-gateDone)
+	}
+}
+
+// close stops the use cases running in background before the pool closed.
+func (a *capitan) close() {
+	a.backup.Close()
+	a.closeBound()
+}
+
+// closeBound stops the database bound use cases.
+func (a *capitan) closeBound() {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	a.basic.Close()
+	a.settings.Close()
+}
+
+// build creates the database bound use cases and the routes, then puts them behind the gate.
+func (a *capitan) build(ctx context.Context) {
+	ucSystem := usecases.NewSystem(ctx)
+	basic := usecases.NewBasic(ctx, a.stream)
+	settings := usecases.NewSettings()
+
+	// HTTP Handler
+	r := router.NewRouter(ucSystem).
+		AddV1BasicRoutes(basic).
+		AddV1StreamRoutes(a.stream).
+		AddV1SystemRoutes(a.backup).
+		AddV1TransferRoutes(usecases.NewTransfer()).
+		AddV1SettingRoutes(settings).
+		AddV1ConfigRoutes(a.reloader)
+
+	a.lock.Lock()
+	a.basic = basic
+	a.settings = settings
+	a.lock.Unlock()
+	a.gate.SetHandler(r.GetHandler())
+}
+
+// Drain implements usecases.Maintainer.
+func (a *capitan) Drain(ctx context.Context) error {
+	a.logger.Info("Enter maintenance")
+	err := a.gate.Drain(ctx)
+	a.closeBound()
+	a.cfg.ClosePool()
+	return err
+}
+
+// Resume implements usecases.Maintainer.
+// The gate is opened on the old use cases if the database failed to reopen, not kept draining.
+// The basic data is refreshed after the gate opened, the maintenance not bound to the upstream.
+func (a *capitan) Resume(rebind func()) error {
+	if err := a.cfg.ReopenDB(); err != nil {
+		a.gate.Resume()
+		a.logger.Errorf("Leave maintenance without database: %v", err)
+		return err
+	}
+	a.build(a.ctx)
+	rebind()
+	a.gate.Resume()
+	a.logger.Info("Leave maintenance")
+	go a.getBasic().Start()
+	return nil
+}
+
+// InMaintenance implements usecases.Runtime.
+func (a *capitan) InMaintenance() bool {
+	return a.gate.InMaintenance()
+}
+
+// GetRefreshStatus implements usecases.Runtime.
+func (a *capitan) GetRefreshStatus() *entity.RefreshStatus {
+	return a.getBasic().GetRefreshStatus()
+}
+
+func (a *capitan) getBasic() usecases.Basic {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.basic
 }
 
 // tryStopProxyServer ROOT_PATH should be set only under docker environment.
@@ -0,0 +1,111 @@
+package capitan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chindada/leopard/pkg/log"
+)
+
+// stopGrace is given to each hook left after the shutdown timeout, e.g. stopping the database.
+const stopGrace = 5 * time.Second
+
+// lifecycle owns the root context given to the use cases, Shutdown runs the hooks in the reverse order of OnStop.
+type lifecycle struct {
+	logger *log.Log
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	lock  sync.Mutex
+	hooks []stopHook
+}
+
+type stopHook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+func newLifecycle(logger *log.Log) *lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &lifecycle{
+		logger: logger,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Context is done once Shutdown reached the hook of CancelRoot.
+func (l *lifecycle) Context() context.Context {
+	return l.ctx
+}
+
+// OnStop adds a hook, the hooks added later are stopped first.
+func (l *lifecycle) OnStop(name string, stop func(ctx context.Context) error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.hooks = append(l.hooks, stopHook{name: name, stop: stop})
+}
+
+// CancelRoot adds the hook cancelling the root context.
+func (l *lifecycle) CancelRoot() {
+	l.OnStop("root context", func(context.Context) error {
+		l.cancel()
+		return nil
+	})
+}
+
+// Shutdown runs every hook even if timeout passed, a hook still blocked after timeout is left behind.
+func (l *lifecycle) Shutdown(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	defer l.cancel()
+
+	l.lock.Lock()
+	hooks := l.hooks
+	l.hooks = nil
+	l.lock.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		start := time.Now()
+		if err := runHook(ctx, h); err != nil {
+			l.logger.Warnf("Stop %s: %v", h.name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+			continue
+		}
+		l.logger.Infof("Stopped %s in %s", h.name, time.Since(start).Round(time.Millisecond))
+	}
+	return errors.Join(errs...)
+}
+
+func runHook(ctx context.Context, h stopHook) error {
+	if ctx.Err() != nil {
+		// out of time, the hook is still given a grace to release the resources
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), stopGrace)
+		defer cancel()
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- h.stop(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// blocking adapts a stop without context to a hook.
+func blocking(stop func()) func(context.Context) error {
+	return func(context.Context) error {
+		stop()
+		return nil
+	}
+}
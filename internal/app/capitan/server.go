@@ -0,0 +1,60 @@
+package capitan
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/chindada/leopard/pkg/log"
+)
+
+// the same timeouts as leopard httpserver, which has no shutdown.
+// The backup transfers clear them per request.
+const (
+	serverReadTimeout       = 5 * time.Second
+	serverReadHeaderTimeout = 5 * time.Second
+	serverWriteTimeout      = 5 * time.Minute
+)
+
+// server is the api server, Shutdown stops accepting and waits the requests not hijacked.
+type server struct {
+	logger *log.Log
+	srv    *http.Server
+}
+
+func newServer(logger *log.Log, handler http.Handler, port string) *server {
+	return &server{
+		logger: logger,
+		srv: &http.Server{
+			Addr:              net.JoinHostPort("", port),
+			Handler:           handler,
+			ErrorLog:          slog.NewLogLogger(slog.NewTextHandler(io.Discard, nil), slog.LevelInfo),
+			ReadTimeout:       serverReadTimeout,
+			ReadHeaderTimeout: serverReadHeaderTimeout,
+			WriteTimeout:      serverWriteTimeout,
+		},
+	}
+}
+
+// Start returns after listening, the serve error after is fatal.
+func (s *server) Start() error {
+	ln, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return err
+	}
+	s.logger.Infof("HTTP Serve On %v", s.srv.Addr)
+	go func() {
+		if sErr := s.srv.Serve(ln); sErr != nil && !errors.Is(sErr, http.ErrServerClosed) {
+			s.logger.Fatalf("API Server error: %s", sErr)
+		}
+	}()
+	return nil
+}
+
+func (s *server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
@@ -4,29 +4,51 @@ package config
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"path/filepath"
-	"strconv"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"text/template"
 	"time"
 
+	// embed time zone database for Schedule.TimeZone.
+	_ "time/tzdata"
+
 	"github.com/chindada/capitan/internal/config/templates"
 	gRPCClient "github.com/chindada/capitan/internal/usecases/grpc/client"
+	"github.com/chindada/capitan/internal/usecases/repo/migrations"
+	"github.com/chindada/leopard/pkg/eventbus"
 	"github.com/chindada/leopard/pkg/log"
 	"github.com/chindada/panther/golang/pb"
 	"github.com/chindada/panther/pkg/client"
 	"github.com/chindada/panther/pkg/launcher"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"google.golang.org/grpc"
+
+	// postgres driver of migrate.
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 )
 
 const (
 	dbName = "capitan"
+
+	grpcStartRetry    = 10
+	grpcRetryInterval = 3 * time.Second
 )
 
+// TopicUpstreamChanged publishes the bool available after the panther grpc connected or lost.
+const TopicUpstreamChanged = "upstream_changed"
+
 // Config -.
 type Config struct {
 	InfraConfig
@@ -34,11 +56,21 @@ type Config struct {
 	vp     *viper.Viper
 	logger *log.Log
 
-	gRPConn *grpc.ClientConn
-	dbPool  client.PGClient
+	gRPConn  *grpc.ClientConn
+	upstream atomic.Bool
+	dbPool   client.PGClient
+	poolLock sync.RWMutex
 
 	rootPath   string
 	needStopDB bool
+
+	tracer *sdktrace.TracerProvider
+
+	// running is the option values in effect, compared by Reload.
+	running    map[string]string
+	reloadLock sync.Mutex
+	// liveLock guards Proxy and Stream, changed by Reload.
+	liveLock sync.RWMutex
 }
 
 var (
@@ -54,39 +86,63 @@ func newConfig() *Config {
 	}
 	return &Config{
 		logger:   logger,
-		vp:       viper.New(),
 		rootPath: filepath.Join(filepath.Dir(ex), ".."),
 	}
 }
 
-func (c *Config) loadEnv() {
-	c.vp.SetDefault("DB_BIN_PATH", "")
-	c.vp.SetDefault("DB_HOST", "127.0.0.1")
-	c.vp.SetDefault("DB_PORT", "5432")
-	c.vp.SetDefault("DB_USER", "postgres")
-	c.vp.SetDefault("DB_PASS", "password")
-	c.vp.SetDefault("DB_POOL_MAX", 90)
-	c.vp.SetDefault("SRV_PORT", "23456")
-	c.vp.SetDefault("HTTP_PORT", "80")
-	c.vp.SetDefault("HTTPS_PORT", "443")
-	c.vp.SetDefault("GRPC_PORT", "56666")
-	c.vp.SetDefault("GRPC_HOST", "127.0.0.1")
-	c.vp.AutomaticEnv()
+// loadConfig reads the config of args, every problem is reported before exit.
+func (c *Config) loadConfig(args []string) {
+	vp, err := load(args)
+	if errors.Is(err, pflag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		c.logger.Fatal(err)
+	}
+	if err = validate(vp); err != nil {
+		c.logger.Fatalf("Invalid config:\n%v", err)
+	}
+	if vp.ConfigFileUsed() != "" {
+		c.logger.Infof("Config file: %s", vp.ConfigFileUsed())
+	}
+	if vp.GetString("db.pass") == defaultDBPass {
+		c.logger.Warn("db.pass is the old default password, allowed by db.allow_default_pass")
+	}
+	c.vp = vp
+	c.running = make(map[string]string, len(options))
+	for _, o := range options {
+		c.running[o.key] = value(vp, o.key)
+	}
 	c.InfraConfig = InfraConfig{
 		Database: Database{
-			BinPath: c.vp.GetString("DB_BIN_PATH"),
-			Host:    c.vp.GetString("DB_HOST"),
-			Port:    c.vp.GetString("DB_PORT"),
-			User:    c.vp.GetString("DB_USER"),
-			Pass:    c.vp.GetString("DB_PASS"),
-			PoolMax: c.vp.GetInt("DB_POOL_MAX"),
+			BinPath:  c.vp.GetString("db.bin_path"),
+			Host:     c.vp.GetString("db.host"),
+			Port:     c.vp.GetString("db.port"),
+			User:     c.vp.GetString("db.user"),
+			Pass:     c.vp.GetString("db.pass"),
+			PoolMax:  c.vp.GetInt("db.pool_max"),
+			Exporter: c.vp.GetBool("db.exporter"),
 		},
 		Server: Server{
-			SRVPort: c.vp.GetString("SRV_PORT"),
+			SRVPort:         c.vp.GetString("srv.port"),
+			ShutdownTimeout: c.vp.GetDuration("srv.shutdown_timeout"),
 		},
 		GRPC: GRPC{
-			Port: c.vp.GetString("GRPC_PORT"),
-			Host: c.vp.GetString("GRPC_HOST"),
+			Port:       c.vp.GetString("grpc.port"),
+			Host:       c.vp.GetString("grpc.host"),
+			TLS:        c.vp.GetBool("grpc.tls"),
+			CAFile:     c.vp.GetString("grpc.ca_file"),
+			ServerName: c.vp.GetString("grpc.server_name"),
+			CertFile:   c.vp.GetString("grpc.cert_file"),
+			KeyFile:    c.vp.GetString("grpc.key_file"),
+			Token:      c.vp.GetString("grpc.token"),
+
+			Timeout:     c.vp.GetDuration("grpc.timeout"),
+			ListTimeout: c.vp.GetDuration("grpc.list_timeout"),
+			RetryTimes:  c.vp.GetInt("grpc.retry_times"),
+		},
+		Stream: Stream{
+			Codes: stringList(c.vp.Get("stream.codes")),
 		},
 		Proxy: Proxy{
 			PidPath:     filepath.Join(c.rootPath, "proxy", "proxy.pid"),
@@ -94,36 +150,79 @@ func (c *Config) loadEnv() {
 			CertPath:    filepath.Join(c.rootPath, "certs", "cert.pem"),
 			KeyPath:     filepath.Join(c.rootPath, "certs", "key.pem"),
 			DhparamPath: filepath.Join(c.rootPath, "certs", "capitan.dhparam"),
-			SRVPort:     c.vp.GetString("SRV_PORT"),
-			HTTPPort:    c.vp.GetString("HTTP_PORT"),
-			HTTPSPort:   c.vp.GetString("HTTPS_PORT"),
+			SRVPort:     c.vp.GetString("srv.port"),
+			HTTPPort:    c.vp.GetString("http.port"),
+			HTTPSPort:   c.vp.GetString("https.port"),
 			AssetsPath:  filepath.Join(c.rootPath, "dist", "assets"),
 			DistPath:    filepath.Join(c.rootPath, "dist"),
 		},
+		Schedule: Schedule{
+			TimeZone:      c.vp.GetString("schedule.timezone"),
+			BasicRefresh:  c.vp.GetString("schedule.basic_refresh"),
+			RetryTimes:    c.vp.GetInt("schedule.retry_times"),
+			RetryInterval: c.vp.GetDuration("schedule.retry_interval"),
+		},
+		Backup: Backup{
+			Path:          filepath.Join(c.rootPath, "db_backup"),
+			JobPath:       filepath.Join(c.rootPath, "db_backup", "jobs.json"),
+			EncryptionKey: c.vp.GetString("backup.encryption_key"),
+			UploadPath:    filepath.Join(c.rootPath, "db_backup", "uploads"),
+			UploadMaxSize: int64(c.vp.GetSizeInBytes("backup.upload_max_size")),
+		},
+		Health: Health{
+			RefreshMaxAge: c.vp.GetDuration("health.refresh_max_age"),
+			MinFreeDisk:   uint64(c.vp.GetSizeInBytes("health.min_free_disk")),
+		},
+		Metrics: Metrics{
+			Token: c.vp.GetString("metrics.token"),
+		},
+		Trace: Trace{
+			Exporter:    c.vp.GetString("trace.exporter"),
+			Endpoint:    c.vp.GetString("trace.endpoint"),
+			Insecure:    c.vp.GetBool("trace.insecure"),
+			File:        c.vp.GetString("trace.file"),
+			SampleRatio: c.vp.GetFloat64("trace.sample_ratio"),
+		},
+	}
+	// validated already
+	c.Metrics.Allow, _ = prefixList(c.vp.Get("metrics.allow"))
+	if c.Metrics.Token == "" && len(c.Metrics.Allow) == 0 {
+		c.logger.Warn("/metrics denies all, set metrics.token or metrics.allow")
 	}
+	c.Schedule.location, _ = time.LoadLocation(c.Schedule.TimeZone)
+	c.setLogLevel(c.running["log.level"])
 }
 
-func (c *Config) writeProxyConfig() {
+// setLogLevel overrides LOG_LEVEL read by the logger with log.level, validated already.
+func (c *Config) setLogLevel(level string) {
+	l, _ := logrus.ParseLevel(level)
+	c.logger.SetLevel(l)
+}
+
+func (c *Config) writeProxyConfig(proxy Proxy) error {
 	var b bytes.Buffer
 	t := template.Must(template.ParseFS(templates.Porxy, "proxy.tmpl"))
-	err := t.Execute(&b, c.Proxy)
-	if err != nil {
-		c.logger.Fatal(err)
-	}
-	err = os.WriteFile(filepath.Join(c.rootPath, "proxy", "conf", "nginx.conf"), b.Bytes(), 0o600)
-	if err != nil {
-		c.logger.Fatal(err)
+	if err := t.Execute(&b, proxy); err != nil {
+		return err
 	}
+	return os.WriteFile(filepath.Join(c.rootPath, "proxy", "conf", "nginx.conf"), b.Bytes(), 0o600)
 }
 
 func Init() {
 	once.Do(func() {
 		c := newConfig()
-		c.loadEnv()
+		c.loadConfig(os.Args[1:])
+		c.setupTracing()
 		c.connectGRPC()
 		c.launchDB()
+		if err := c.migrateLocalScheme(); err != nil {
+			c.logger.Fatal(err)
+		}
 		c.setPostgresPool()
-		c.writeProxyConfig()
+		prometheus.MustRegister(newPoolCollector(c))
+		if err := c.writeProxyConfig(c.Proxy); err != nil {
+			c.logger.Fatal(err)
+		}
 		singleton = c
 	})
 }
@@ -166,45 +265,133 @@ func (c *Config) launchDB() {
 	}
 }
 
-func (c *Config) setPostgresPool() {
-	var path string
+func (c *Config) postgresURL() string {
+	return c.DatabaseURL(dbName)
+}
+
+// DatabaseURL is the url of database name on the same server, e.g. a scratch database.
+func (c *Config) DatabaseURL(name string) string {
 	dbt := launcher.Get()
 	if socketPath := dbt.GetSocketPath(); socketPath != "" {
-		path = fmt.Sprintf("postgres://%s:%s@?host=%s&port=%s&dbname=%s&sslmode=disable",
+		return fmt.Sprintf("postgres://%s:%s@?host=%s&port=%s&dbname=%s&sslmode=disable",
 			c.Database.User, c.Database.Pass,
-			socketPath, c.Database.Port, dbName)
+			socketPath, c.Database.Port, name)
+	}
+	return fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable",
+		c.Database.User, c.Database.Pass,
+		net.JoinHostPort(c.Database.Host, c.Database.Port), name)
+}
+
+// DatabaseArgs are the connection flags of database name for the postgres tools, without the password.
+func (c *Config) DatabaseArgs(name string) []string {
+	host := c.Database.Host
+	if socketPath := launcher.Get().GetSocketPath(); socketPath != "" {
+		host = socketPath
+	}
+	return []string{"-h", host, "-p", c.Database.Port, "-U", c.Database.User, "-d", name}
+}
+
+// DatabaseEnv is the environment of the postgres tools, the password is kept out of the args.
+func (c *Config) DatabaseEnv() []string {
+	return append(os.Environ(), "PGPASSWORD="+c.Database.Pass, "PGSSLMODE=disable")
+}
+
+// migrateLocalScheme applies the schema owned by capitan, must run after launcher MigrateScheme.
+func (c *Config) migrateLocalScheme() error {
+	source, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return err
+	}
+	m, err := migrate.NewWithSourceInstance(
+		"iofs", source,
+		fmt.Sprintf("%s&x-migrations-table=%s", c.postgresURL(), migrations.Table),
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = m.Close()
+	}()
+	if err = m.Up(); err != nil {
+		if errors.Is(err, migrate.ErrNoChange) {
+			c.logger.Info("local migrate no change")
+			return nil
+		}
+		return fmt.Errorf("local migrate error: %w", err)
+	}
+	c.logger.Info("local migrate success")
+	return nil
+}
+
+func (c *Config) setPostgresPool() {
+	if err := c.openPostgresPool(); err != nil {
+		c.logger.Fatal(err)
+	}
+}
+
+func (c *Config) openPostgresPool() error {
+	if socketPath := launcher.Get().GetSocketPath(); socketPath != "" {
 		c.logger.Infof("database socket path: %s", socketPath)
 	} else {
-		path = fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable",
-			c.Database.User, c.Database.Pass,
-			net.JoinHostPort(c.Database.Host, c.Database.Port), dbName)
 		c.logger.Infof("database host: %s", c.Database.Host)
 	}
 	pg, err := client.New(
-		path,
+		c.postgresURL(),
 		client.MaxPoolSize(c.Database.PoolMax),
 		client.AddLogger(c.logger),
 	)
 	if err != nil {
-		c.logger.Fatal(err)
+		return err
 	}
+	c.poolLock.Lock()
 	c.dbPool = pg
+	c.poolLock.Unlock()
+	return nil
 }
 
-func (c *Config) runExporter(dbt launcher.PGLauncher) {
-	needExport, ok := os.LookupEnv("DB_EXPORTER")
-	if ok {
-		need, err := strconv.ParseBool(needExport)
-		if err != nil {
-			c.logger.Warn(err)
-		} else if need {
-			if err = dbt.RunExporter(); err != nil {
-				c.logger.Warn(err)
-			}
+// ClosePool closes the pool before the database restored, GetPostgresPool is not usable until ReopenDB.
+func (c *Config) ClosePool() {
+	c.poolLock.Lock()
+	defer c.poolLock.Unlock()
+	if c.dbPool != nil {
+		c.dbPool.Close()
+		c.dbPool = nil
+	}
+}
+
+// ReopenDB starts the restored database, migrates the scheme and opens a new pool.
+func (c *Config) ReopenDB() error {
+	dbt := launcher.Get()
+	isRunning, err := dbt.IsRunning()
+	if err != nil {
+		return err
+	}
+	if !isRunning {
+		if err = dbt.StartDB(); err != nil {
+			return err
 		}
+		c.needStopDB = true
+	}
+	if err = dbt.MigrateScheme(nil); err != nil {
+		return err
+	}
+	if err = c.migrateLocalScheme(); err != nil {
+		return err
+	}
+	return c.openPostgresPool()
+}
+
+func (c *Config) runExporter(dbt launcher.PGLauncher) {
+	if !c.Database.Exporter {
+		return
+	}
+	if err := dbt.RunExporter(); err != nil {
+		c.logger.Warn(err)
 	}
 }
 
+// connectGRPC waits the first connection up to grpcStartRetry times, then starts in degraded mode,
+// the connection is watched and reconnected in background anyway.
 func (c *Config) connectGRPC() {
 	if c.InfraConfig.GRPC.Host == "" {
 		c.logger.Fatal("GRPC host is not set")
@@ -212,44 +399,85 @@ func (c *Config) connectGRPC() {
 	if c.InfraConfig.GRPC.Port == "" {
 		c.logger.Fatal("GRPC port is not set")
 	}
-	retry := 10
-	c.logger.Infof("Connecting to %s...", net.JoinHostPort(c.InfraConfig.GRPC.Host, c.InfraConfig.GRPC.Port))
-	for i := range retry {
-		if c.tryConnectGRPC() {
-			return
-		}
-		if i < retry-1 {
-			c.logger.Infof("Waiting 3 second before next retry")
-			<-time.After(time.Second * 3)
-		}
+	addr := net.JoinHostPort(c.InfraConfig.GRPC.Host, c.InfraConfig.GRPC.Port)
+	gRPConn, err := gRPCClient.NewClient(addr, c.grpcOptions()...)
+	if err != nil {
+		c.logger.Fatalf("Invalid gRPC server %s: %v", addr, err)
 	}
-	if c.gRPConn == nil {
-		c.logger.Fatalf("Failed to connect to gRPC server %s:%s after %d retries",
-			c.InfraConfig.GRPC.Host, c.InfraConfig.GRPC.Port, retry)
+	c.gRPConn = gRPConn
+	c.logger.Infof("Connecting to %s...", addr)
+	connected := make(chan struct{})
+	go c.watchGRPC(connected)
+	select {
+	case <-connected:
+	case <-time.After(grpcStartRetry * grpcRetryInterval):
+		c.logger.Warnf("gRPC server %s unavailable after %d retries, start in degraded mode", addr, grpcStartRetry)
 	}
 }
 
-func (c *Config) tryConnectGRPC() bool {
-	gRPConn, err := gRPCClient.NewInsecureClient(net.JoinHostPort(c.InfraConfig.GRPC.Host, c.InfraConfig.GRPC.Port))
-	if err != nil {
-		return false
+// grpcOptions retries the instrument lists only, the read only rpcs of panther.
+func (c *Config) grpcOptions() []gRPCClient.Option {
+	g := c.InfraConfig.GRPC
+	lists := []string{
+		pb.BasicInterface_GetAllStockDetail_FullMethodName,
+		pb.BasicInterface_GetAllFutureDetail_FullMethodName,
+		pb.BasicInterface_GetAllOptionDetail_FullMethodName,
+	}
+	opts := []gRPCClient.Option{
+		gRPCClient.Timeout(g.Timeout),
+		gRPCClient.Retry(g.RetryTimes, lists...),
+		gRPCClient.Untraced(pb.HealthInterface_HealthChannel_FullMethodName),
+	}
+	for _, m := range lists {
+		opts = append(opts, gRPCClient.MethodTimeout(m, g.ListTimeout))
+	}
+	if g.TLS {
+		opts = append(opts, gRPCClient.TLS(g.CAFile, g.ServerName), gRPCClient.ClientCert(g.CertFile, g.KeyFile))
+	} else if ip := net.ParseIP(g.Host); (ip == nil || !ip.IsLoopback()) && g.Host != "localhost" {
+		c.logger.Warnf("gRPC to %s is plaintext, set grpc.tls", g.Host)
 	}
-	healthClient := pb.NewHealthInterfaceClient(gRPConn)
-	stream, cErr := healthClient.HealthChannel(context.Background())
-	if cErr != nil {
-		return false
+	if g.Token != "" {
+		opts = append(opts, gRPCClient.BearerToken(g.Token))
 	}
-	go func() {
-		for {
+	return opts
+}
+
+// watchGRPC keeps the health channel open, TopicUpstreamChanged is published on connected and lost.
+func (c *Config) watchGRPC(connected chan struct{}) {
+	var once sync.Once
+	healthClient := pb.NewHealthInterfaceClient(c.gRPConn)
+	for {
+		stream, err := healthClient.HealthChannel(context.Background())
+		if err != nil {
+			<-time.After(grpcRetryInterval)
+			continue
+		}
+		c.setUpstream(true)
+		once.Do(func() {
+			close(connected)
+		})
+		for err == nil {
 			_, err = stream.Recv()
-			if err != nil {
-				c.logger.Fatal("Lost connection to gRPC server")
-			}
 		}
-	}()
-	c.gRPConn = gRPConn
-	c.logger.Info("Connected")
-	return true
+		c.logger.Warnf("Lost connection to gRPC server: %v, degraded until reconnected", err)
+		c.setUpstream(false)
+		<-time.After(grpcRetryInterval)
+	}
+}
+
+func (c *Config) setUpstream(available bool) {
+	if c.upstream.Swap(available) == available {
+		return
+	}
+	if available {
+		c.logger.Info("Connected")
+	}
+	eventbus.Get().PublishTopicEvent(TopicUpstreamChanged, available)
+}
+
+// UpstreamAvailable is true while the health channel of panther is open.
+func (c *Config) UpstreamAvailable() bool {
+	return c.upstream.Load()
 }
 
 func (c *Config) GetGRPCConn() *grpc.ClientConn {
@@ -259,7 +487,30 @@ func (c *Config) GetGRPCConn() *grpc.ClientConn {
 	return c.gRPConn
 }
 
+// GetProxy is the proxy in effect, the ports changed by Reload.
+func (c *Config) GetProxy() Proxy {
+	c.liveLock.RLock()
+	defer c.liveLock.RUnlock()
+	return c.Proxy
+}
+
+// GetStreamCodes is the stream codes in effect, changed by Reload.
+func (c *Config) GetStreamCodes() []string {
+	c.liveLock.RLock()
+	defer c.liveLock.RUnlock()
+	return slices.Clone(c.Stream.Codes)
+}
+
+// LookupPostgresPool returns nil if the pool closed, e.g. in maintenance.
+func (c *Config) LookupPostgresPool() client.PGClient {
+	c.poolLock.RLock()
+	defer c.poolLock.RUnlock()
+	return c.dbPool
+}
+
 func (c *Config) GetPostgresPool() client.PGClient {
+	c.poolLock.RLock()
+	defer c.poolLock.RUnlock()
 	if c.dbPool == nil {
 		c.logger.Fatal("postgres not connected")
 	}
@@ -267,9 +518,7 @@ func (c *Config) GetPostgresPool() client.PGClient {
 }
 
 func (c *Config) CloseDB() {
-	if c.dbPool != nil {
-		c.dbPool.Close()
-	}
+	c.ClosePool()
 	if !c.needStopDB {
 		return
 	}
@@ -1,30 +1,64 @@
 package config
 
+import (
+	"net/netip"
+	"time"
+)
+
 type InfraConfig struct {
 	Database Database
 	Server   Server
 	Proxy    Proxy
 	GRPC     GRPC
+	Stream   Stream
+	Schedule Schedule
+	Backup   Backup
+	Health   Health
+	Metrics  Metrics
+	Trace    Trace
 }
 
 type Database struct {
-	BinPath string
-	Host    string
-	Port    string
-	User    string
-	Pass    string
-	PoolMax int
+	BinPath  string
+	Host     string
+	Port     string
+	User     string
+	Pass     string
+	PoolMax  int
+	Exporter bool
 }
 
+// GRPC is insecure unless TLS, CAFile empty uses the system roots, CertFile and KeyFile are for mutual tls.
+// The files are read again once rotated. Token is the bearer token of every rpc.
+// Timeout is the deadline of an rpc without one, ListTimeout of the instrument lists.
 type GRPC struct {
 	Port string
 	Host string
+
+	TLS        bool
+	CAFile     string
+	ServerName string
+	CertFile   string
+	KeyFile    string
+	Token      string
+
+	Timeout     time.Duration
+	ListTimeout time.Duration
+	RetryTimes  int
+}
+
+// Stream Codes are the futures ticks subscribed without any client, read by GetStreamCodes.
+type Stream struct {
+	Codes []string
 }
 
+// Server ShutdownTimeout bounds the whole shutdown, the database is closed anyway after.
 type Server struct {
-	SRVPort string
+	SRVPort         string
+	ShutdownTimeout time.Duration
 }
 
+// Proxy is read by GetProxy, the ports changed by Reload.
 type Proxy struct {
 	PidPath  string
 	MimePath string
@@ -40,3 +74,52 @@ type Proxy struct {
 	AssetsPath string
 	DistPath   string
 }
+
+// Backup Path is where the backups kept, the same as launcher.
+// EncryptionKey is the passphrase of the backups at rest, empty means plaintext.
+// UploadPath keeps the partial uploads, UploadMaxSize is the limit of one archive in bytes.
+type Backup struct {
+	Path          string
+	JobPath       string
+	EncryptionKey string
+	UploadPath    string
+	UploadMaxSize int64
+}
+
+type Schedule struct {
+	TimeZone      string
+	BasicRefresh  string
+	RetryTimes    int
+	RetryInterval time.Duration
+
+	location *time.Location
+}
+
+// Location is the time zone of all cron expressions.
+func (s Schedule) Location() *time.Location {
+	if s.location == nil {
+		return time.Local
+	}
+	return s.location
+}
+
+// Health thresholds of readiness degraded.
+type Health struct {
+	RefreshMaxAge time.Duration
+	MinFreeDisk   uint64
+}
+
+// Metrics allows /metrics by the bearer Token, or the remote address in Allow if not through the proxy, none if both empty.
+type Metrics struct {
+	Token string
+	Allow []netip.Prefix
+}
+
+// Trace is exported by otlp to Endpoint, or by stdout written to File if set, none is off.
+type Trace struct {
+	Exporter    string
+	Endpoint    string
+	Insecure    bool
+	File        string
+	SampleRatio float64
+}
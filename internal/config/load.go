@@ -0,0 +1,305 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// configEnv is the config file if --config not given.
+	configEnv = "CAPITAN_CONFIG"
+	// redacted replaces the secrets in print.
+	redacted = "******"
+	// defaultDBPass is the old default, refused unless db.allow_default_pass.
+	defaultDBPass = "password"
+)
+
+// option is a config key, the env is the key in upper case with dots as underscores, e.g. db.host is DB_HOST.
+type option struct {
+	key    string
+	def    any
+	usage  string
+	secret bool
+	// live is applied by Reload, the others need a restart.
+	live bool
+}
+
+var options = []option{
+	{key: "log.level", def: "info", usage: "trace, debug, info, warn or error", live: true},
+	{key: "db.bin_path", def: "", usage: "postgres binary root, empty uses the bundled one"},
+	{key: "db.host", def: "127.0.0.1", usage: "postgres listen host"},
+	{key: "db.port", def: "5432", usage: "postgres port"},
+	{key: "db.user", def: "postgres", usage: "postgres user"},
+	{key: "db.pass", def: "", usage: "postgres password, required", secret: true},
+	{key: "db.allow_default_pass", def: false, usage: "accept the old default db.pass of the existing databases"},
+	{key: "db.pool_max", def: 90, usage: "max connections of the pool"},
+	{key: "db.exporter", def: false, usage: "run the postgres exporter"},
+	{key: "srv.port", def: "23456", usage: "api server port"},
+	{key: "srv.shutdown_timeout", def: "30s", usage: "max wait of the connections and jobs on shutdown"},
+	{key: "http.port", def: "80", usage: "proxy http port", live: true},
+	{key: "https.port", def: "443", usage: "proxy https port", live: true},
+	{key: "grpc.host", def: "127.0.0.1", usage: "panther grpc host"},
+	{key: "grpc.port", def: "56666", usage: "panther grpc port"},
+	{key: "grpc.tls", def: false, usage: "connect panther by tls"},
+	{key: "grpc.ca_file", def: "", usage: "ca of the panther cert, empty uses the system roots"},
+	{key: "grpc.server_name", def: "", usage: "name verified in the panther cert, empty is grpc.host"},
+	{key: "grpc.cert_file", def: "", usage: "client cert of mutual tls"},
+	{key: "grpc.key_file", def: "", usage: "client key of mutual tls"},
+	{key: "grpc.token", def: "", usage: "bearer token sent to panther, requires grpc.tls", secret: true},
+	{key: "grpc.timeout", def: "10s", usage: "deadline of a panther rpc if the caller has none"},
+	{key: "grpc.list_timeout", def: "2m", usage: "deadline of the instrument lists of panther"},
+	{key: "grpc.retry_times", def: 2, usage: "retries of an idempotent panther rpc if unavailable"},
+	{key: "stream.codes", def: "TXFG5,MXFG5,TMFG5", usage: "futures ticks subscribed on start, comma separated", live: true},
+	{key: "schedule.timezone", def: "Asia/Taipei", usage: "time zone of all cron expressions"},
+	{key: "schedule.basic_refresh", def: "30 7,14 * * 1-5", usage: "cron of the basic data refresh"},
+	{key: "schedule.retry_times", def: 3, usage: "retries of a failed refresh"},
+	{key: "schedule.retry_interval", def: "1m", usage: "wait between the retries"},
+	{key: "backup.encryption_key", def: "", usage: "passphrase of the backups at rest, empty is plaintext", secret: true},
+	{key: "backup.upload_max_size", def: "10GB", usage: "max size of an uploaded backup"},
+	{key: "health.refresh_max_age", def: "96h", usage: "readiness degraded if the last instrument refresh is older"},
+	{key: "health.min_free_disk", def: "1GB", usage: "readiness degraded if the free space of the backups is less"},
+	{key: "trace.exporter", def: TraceExporterNone, usage: "none, otlp or stdout"},
+	{key: "trace.endpoint", def: "127.0.0.1:4317", usage: "otlp grpc endpoint"},
+	{key: "trace.insecure", def: true, usage: "connect the otlp endpoint without tls"},
+	{key: "trace.file", def: "", usage: "file the stdout exporter writes to, empty is stdout"},
+	{key: "trace.sample_ratio", def: 1.0, usage: "ratio of the traces sampled, 0 to 1"},
+	{key: "metrics.token", def: "", usage: "bearer token allowed to scrape /metrics", secret: true},
+	{key: "metrics.allow", def: "", usage: "ips or cidrs allowed to scrape /metrics without token, comma separated, not through the proxy"},
+}
+
+// load layers the defaults, the config file, env and flags, the later wins.
+func load(args []string) (*viper.Viper, error) {
+	vp := viper.New()
+	fs := pflag.NewFlagSet("capitan", pflag.ContinueOnError)
+	configFile := fs.String("config", "", "config file in yaml or toml, or env "+configEnv)
+	for _, o := range options {
+		vp.SetDefault(o.key, o.def)
+		fs.String(o.key, "", o.usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	for _, o := range options {
+		if err := vp.BindPFlag(o.key, fs.Lookup(o.key)); err != nil {
+			return nil, err
+		}
+	}
+	if *configFile == "" {
+		*configFile = os.Getenv(configEnv)
+	}
+	if *configFile != "" {
+		vp.SetConfigFile(*configFile)
+		if err := vp.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("read config %s: %w", *configFile, err)
+		}
+	}
+	vp.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	vp.AutomaticEnv()
+	return vp, nil
+}
+
+// validate reports every problem at once, the unknown keys in the file included.
+func validate(vp *viper.Viper) error {
+	errs := []error{}
+	if vp.ConfigFileUsed() != "" {
+		known := make(map[string]struct{}, len(options))
+		for _, o := range options {
+			known[o.key] = struct{}{}
+		}
+		file := viper.New()
+		file.SetConfigFile(vp.ConfigFileUsed())
+		if err := file.ReadInConfig(); err != nil {
+			errs = append(errs, err)
+		}
+		for _, k := range file.AllKeys() {
+			if _, ok := known[k]; !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown key", k))
+			}
+		}
+	}
+	if _, err := logrus.ParseLevel(vp.GetString("log.level")); err != nil {
+		errs = append(errs, fmt.Errorf("log.level: %w", err))
+	}
+	for _, k := range []string{"db.host", "db.user", "db.pass", "grpc.host"} {
+		if vp.GetString(k) == "" {
+			errs = append(errs, fmt.Errorf("%s: required", k))
+		}
+	}
+	for _, k := range []string{"db.port", "srv.port", "http.port", "https.port", "grpc.port"} {
+		if port, err := strconv.Atoi(vp.GetString(k)); err != nil || port < 1 || port > 65535 {
+			errs = append(errs, fmt.Errorf("%s: invalid port %q", k, vp.GetString(k)))
+		}
+	}
+	if n, err := cast.ToIntE(vp.Get("db.pool_max")); err != nil || n < 1 {
+		errs = append(errs, fmt.Errorf("db.pool_max: must be a positive integer, got %v", vp.Get("db.pool_max")))
+	}
+	allowDefault, err := cast.ToBoolE(vp.Get("db.allow_default_pass"))
+	if err != nil {
+		errs = append(errs, fmt.Errorf("db.allow_default_pass: %w", err))
+	}
+	if vp.GetString("db.pass") == defaultDBPass && !allowDefault {
+		errs = append(errs, fmt.Errorf("db.pass: must not be %q, or set db.allow_default_pass", defaultDBPass))
+	}
+	if _, err := cast.ToBoolE(vp.Get("db.exporter")); err != nil {
+		errs = append(errs, fmt.Errorf("db.exporter: %w", err))
+	}
+	if _, err := time.LoadLocation(vp.GetString("schedule.timezone")); err != nil {
+		errs = append(errs, fmt.Errorf("schedule.timezone: %w", err))
+	}
+	if _, err := cron.ParseStandard(vp.GetString("schedule.basic_refresh")); err != nil {
+		errs = append(errs, fmt.Errorf("schedule.basic_refresh: %w", err))
+	}
+	if n, err := cast.ToIntE(vp.Get("schedule.retry_times")); err != nil || n < 0 {
+		errs = append(errs, fmt.Errorf("schedule.retry_times: must not be negative, got %v", vp.Get("schedule.retry_times")))
+	}
+	errs = append(errs, validateGRPC(vp)...)
+	if d, err := cast.ToDurationE(vp.Get("srv.shutdown_timeout")); err != nil || d <= 0 {
+		errs = append(errs, fmt.Errorf("srv.shutdown_timeout: must be a positive duration, got %v", vp.Get("srv.shutdown_timeout")))
+	}
+	if d, err := cast.ToDurationE(vp.Get("schedule.retry_interval")); err != nil || d <= 0 {
+		errs = append(errs, fmt.Errorf("schedule.retry_interval: must be a positive duration, got %v", vp.Get("schedule.retry_interval")))
+	}
+	if vp.GetSizeInBytes("backup.upload_max_size") == 0 {
+		errs = append(errs, fmt.Errorf("backup.upload_max_size: invalid size %q", vp.GetString("backup.upload_max_size")))
+	}
+	if d, err := cast.ToDurationE(vp.Get("health.refresh_max_age")); err != nil || d <= 0 {
+		errs = append(errs, fmt.Errorf("health.refresh_max_age: must be a positive duration, got %v", vp.Get("health.refresh_max_age")))
+	}
+	if vp.GetSizeInBytes("health.min_free_disk") == 0 {
+		errs = append(errs, fmt.Errorf("health.min_free_disk: invalid size %q", vp.GetString("health.min_free_disk")))
+	}
+	switch vp.GetString("trace.exporter") {
+	case TraceExporterNone, TraceExporterOTLP, TraceExporterStdout:
+	default:
+		errs = append(errs, fmt.Errorf("trace.exporter: must be none, otlp or stdout, got %q", vp.GetString("trace.exporter")))
+	}
+	if _, err := cast.ToBoolE(vp.Get("trace.insecure")); err != nil {
+		errs = append(errs, fmt.Errorf("trace.insecure: %w", err))
+	}
+	if r, err := cast.ToFloat64E(vp.Get("trace.sample_ratio")); err != nil || r < 0 || r > 1 {
+		errs = append(errs, fmt.Errorf("trace.sample_ratio: must be 0 to 1, got %v", vp.Get("trace.sample_ratio")))
+	}
+	if _, err := prefixList(vp.Get("metrics.allow")); err != nil {
+		errs = append(errs, fmt.Errorf("metrics.allow: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// validateGRPC checks the files readable, they are parsed on connect.
+func validateGRPC(vp *viper.Viper) []error {
+	errs := []error{}
+	enabled, err := cast.ToBoolE(vp.Get("grpc.tls"))
+	if err != nil {
+		errs = append(errs, fmt.Errorf("grpc.tls: %w", err))
+	}
+	for _, k := range []string{"grpc.ca_file", "grpc.cert_file", "grpc.key_file"} {
+		path := vp.GetString(k)
+		if path == "" {
+			continue
+		}
+		if !enabled {
+			errs = append(errs, fmt.Errorf("%s: set without grpc.tls", k))
+		}
+		if _, err = os.Stat(path); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", k, err))
+		}
+	}
+	if vp.GetString("grpc.token") != "" && !enabled {
+		errs = append(errs, errors.New("grpc.token: set without grpc.tls"))
+	}
+	if (vp.GetString("grpc.cert_file") == "") != (vp.GetString("grpc.key_file") == "") {
+		errs = append(errs, errors.New("grpc.cert_file: must be set with grpc.key_file"))
+	}
+	for _, k := range []string{"grpc.timeout", "grpc.list_timeout"} {
+		if d, dErr := cast.ToDurationE(vp.Get(k)); dErr != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("%s: must be a positive duration, got %v", k, vp.Get(k)))
+		}
+	}
+	if n, nErr := cast.ToIntE(vp.Get("grpc.retry_times")); nErr != nil || n < 0 {
+		errs = append(errs, fmt.Errorf("grpc.retry_times: must not be negative, got %v", vp.Get("grpc.retry_times")))
+	}
+	return errs
+}
+
+// stringList takes a list or a comma separated string, the blanks are dropped.
+func stringList(v any) []string {
+	if s, ok := v.(string); ok {
+		v = strings.Split(s, ",")
+	}
+	list := []string{}
+	for _, s := range cast.ToStringSlice(v) {
+		if s = strings.TrimSpace(s); s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// prefixList takes the ips or cidrs of stringList, an ip is the prefix of itself only.
+func prefixList(v any) ([]netip.Prefix, error) {
+	list := []netip.Prefix{}
+	for _, s := range stringList(v) {
+		if strings.Contains(s, "/") {
+			prefix, err := netip.ParsePrefix(s)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return list, nil
+}
+
+// value is the option of vp as compared by Reload.
+func value(vp *viper.Viper, key string) string {
+	if key == "stream.codes" {
+		return strings.Join(stringList(vp.Get(key)), ",")
+	}
+	return cast.ToString(vp.Get(key))
+}
+
+// Print writes the effective config of args as yaml with the secrets redacted, the problems are returned after.
+func Print(w io.Writer, args []string) error {
+	vp, err := load(args)
+	if err != nil {
+		return err
+	}
+	out := viper.New()
+	for _, o := range options {
+		v := vp.Get(o.key)
+		if o.secret && cast.ToString(v) != "" {
+			v = redacted
+		}
+		out.Set(o.key, v)
+	}
+	source := vp.ConfigFileUsed()
+	if source == "" {
+		source = "none"
+	}
+	if _, err = fmt.Fprintf(w, "# config file: %s\n", source); err != nil {
+		return err
+	}
+	out.SetConfigType("yaml")
+	if err = out.WriteConfigTo(w); err != nil {
+		return err
+	}
+	return validate(vp)
+}
@@ -0,0 +1,57 @@
+package config
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// poolCollector reads the stats of the pool on each scrape, nothing collected while the pool is closed for restore.
+type poolCollector struct {
+	c *Config
+
+	totalConns    *prometheus.Desc
+	acquiredConns *prometheus.Desc
+	idleConns     *prometheus.Desc
+	maxConns      *prometheus.Desc
+	acquireCount  *prometheus.Desc
+	acquireWait   *prometheus.Desc
+	emptyAcquire  *prometheus.Desc
+}
+
+func newPoolCollector(c *Config) *poolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("capitan", "postgres_pool", name), help, nil, nil)
+	}
+	return &poolCollector{
+		c:             c,
+		totalConns:    desc("total_conns", "Connections of the pool."),
+		acquiredConns: desc("acquired_conns", "Connections in use."),
+		idleConns:     desc("idle_conns", "Connections idle."),
+		maxConns:      desc("max_conns", "Max connections of the pool."),
+		acquireCount:  desc("acquires_total", "Connections acquired."),
+		acquireWait:   desc("acquire_wait_seconds_total", "Time waited to acquire a connection."),
+		emptyAcquire:  desc("empty_acquires_total", "Acquires waited as the pool was empty."),
+	}
+}
+
+func (p *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- p.totalConns
+	ch <- p.acquiredConns
+	ch <- p.idleConns
+	ch <- p.maxConns
+	ch <- p.acquireCount
+	ch <- p.acquireWait
+	ch <- p.emptyAcquire
+}
+
+func (p *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	pg := p.c.LookupPostgresPool()
+	if pg == nil {
+		return
+	}
+	stat := pg.Pool().Stat()
+	ch <- prometheus.MustNewConstMetric(p.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(p.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(p.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(p.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(p.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(p.acquireWait, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(p.emptyAcquire, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ErrInvalid is returned by Reload if the config fails to load or validate.
+var ErrInvalid = errors.New("config invalid")
+
+// Reload reads the config of the start args again, the live keys changed are applied and returned as applied,
+// the others changed are returned as restart, kept as is until the restart.
+// Nothing is applied if the config is invalid or the proxy fails to reload.
+func (c *Config) Reload() (applied, restart []string, err error) {
+	c.reloadLock.Lock()
+	defer c.reloadLock.Unlock()
+
+	vp, err := load(os.Args[1:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	if err = validate(vp); err != nil {
+		return nil, nil, fmt.Errorf("%w:\n%w", ErrInvalid, err)
+	}
+	for _, o := range options {
+		if value(vp, o.key) == c.running[o.key] {
+			continue
+		}
+		if o.live {
+			applied = append(applied, o.key)
+		} else {
+			restart = append(restart, o.key)
+		}
+	}
+
+	running := c.GetProxy()
+	proxy := running
+	proxy.HTTPPort = vp.GetString("http.port")
+	proxy.HTTPSPort = vp.GetString("https.port")
+	if proxy != running {
+		if err = c.writeProxyConfig(proxy); err != nil {
+			return nil, nil, err
+		}
+		if err = c.reloadProxy(); err != nil {
+			// back to the running one, nginx keeps the old config if the reload failed
+			_ = c.writeProxyConfig(running)
+			return nil, nil, err
+		}
+	}
+	for _, k := range applied {
+		c.running[k] = value(vp, k)
+	}
+	c.liveLock.Lock()
+	c.Proxy = proxy
+	c.Stream.Codes = stringList(c.running["stream.codes"])
+	c.liveLock.Unlock()
+	c.setLogLevel(c.running["log.level"])
+	if len(applied) > 0 || len(restart) > 0 {
+		c.logger.Infof("Config reloaded, applied: %v, restart required: %v", applied, restart)
+	}
+	return applied, restart, nil
+}
+
+// reloadProxy sends SIGHUP to the proxy of PidPath, skipped if the proxy is not running.
+func (c *Config) reloadProxy() error {
+	pid, err := os.ReadFile(c.GetProxy().PidPath)
+	if errors.Is(err, os.ErrNotExist) {
+		c.logger.Info("Proxy is not running, config written only")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	pidInt, err := strconv.Atoi(strings.TrimSpace(string(pid)))
+	if err != nil {
+		return fmt.Errorf("proxy pid %q: %w", pid, err)
+	}
+	p, err := os.FindProcess(pidInt)
+	if err != nil {
+		return err
+	}
+	return p.Signal(syscall.SIGHUP)
+}
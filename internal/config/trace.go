@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/chindada/capitan/internal/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.30.0"
+)
+
+const (
+	TraceExporterNone   = "none"
+	TraceExporterOTLP   = "otlp"
+	TraceExporterStdout = "stdout"
+
+	serviceName = "capitan"
+)
+
+// setupTracing sets the global tracer provider, kept noop if none. The trace context is propagated anyway.
+func (c *Config) setupTracing() {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if c.Trace.Exporter == TraceExporterNone {
+		return
+	}
+	exporter, err := c.newTraceExporter()
+	if err != nil {
+		c.logger.Fatalf("Trace exporter %s: %v", c.Trace.Exporter, err)
+	}
+	c.tracer = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.Trace.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(version.GetCore().GetVersion()),
+		)),
+	)
+	otel.SetTracerProvider(c.tracer)
+	c.logger.Infof("Tracing exported by %s, sample ratio %v", c.Trace.Exporter, c.Trace.SampleRatio)
+}
+
+// newTraceExporter connects otlp lazily, the spans are dropped while the collector is down.
+func (c *Config) newTraceExporter() (sdktrace.SpanExporter, error) {
+	if c.Trace.Exporter == TraceExporterOTLP {
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(c.Trace.Endpoint)}
+		if c.Trace.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(context.Background(), opts...)
+	}
+	var w io.Writer = os.Stdout
+	if c.Trace.File != "" {
+		f, err := os.OpenFile(c.Trace.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		w = f
+	}
+	return stdouttrace.New(stdouttrace.WithWriter(w))
+}
+
+// ShutdownTracing flushes the spans buffered.
+func (c *Config) ShutdownTracing(ctx context.Context) error {
+	if c.tracer == nil {
+		return nil
+	}
+	return c.tracer.Shutdown(ctx)
+}
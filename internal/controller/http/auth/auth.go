@@ -82,9 +82,9 @@ func hTTPStatusMessageFunc(e error, _ *gin.Context) string {
 }
 
 func unauthorized(c *gin.Context, code int, message string) {
-	resp.Success(c, code, &pb.APIResponse{
-		Code:     int64(code),
-		Response: message,
+	resp.Fail(c, code, &resp.APIError{
+		Code:    int64(code),
+		Message: message,
 	})
 }
 
@@ -137,3 +137,25 @@ func payloadFunc(data any) jwt.MapClaims {
 	}
 	return nil
 }
+
+// NewAdminMiddleware only allows the user with role admin or root, must be used after the jwt middleware.
+func NewAdminMiddleware(system usecases.System) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := jwt.ExtractClaims(c)
+		username, _ := claims["username"].(string)
+		if username == "" {
+			resp.Fail(c, http.StatusUnauthorized, resp.ErrPermissionDenied)
+			return
+		}
+		user, err := system.GetUser(c, username)
+		if err != nil {
+			resp.Fail(c, http.StatusInternalServerError, err)
+			return
+		}
+		if user.GetBasic().GetRole() < pb.UserRole_ADMIN {
+			resp.Fail(c, http.StatusForbidden, resp.ErrPermissionDenied)
+			return
+		}
+		c.Next()
+	}
+}
@@ -18,4 +18,12 @@ var (
 	ErrEmailFormatInvalid = &APIError{Code: -106, Message: "email format invalid"}
 	ErrEmailRequired      = &APIError{Code: -107, Message: "email required"}
 	ErrCannotDeleteSelf   = &APIError{Code: -108, Message: "cannot delete self"}
+	ErrUnderlyingRequired = &APIError{Code: -109, Message: "underlying required"}
+	ErrMonthRequired      = &APIError{Code: -110, Message: "month required"}
+	ErrPermissionDenied   = &APIError{Code: -111, Message: "permission denied"}
+	ErrDateFormatInvalid  = &APIError{Code: -112, Message: "date format invalid"}
+	ErrMaintenance        = &APIError{Code: -114, Message: "under maintenance"}
+	ErrFileRequired       = &APIError{Code: -115, Message: "file required"}
+	ErrOffsetInvalid      = &APIError{Code: -116, Message: "offset invalid"}
+	ErrKindInvalid        = &APIError{Code: -117, Message: "kind invalid"}
 )
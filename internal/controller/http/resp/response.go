@@ -9,13 +9,28 @@ import (
 	"github.com/gin-gonic/gin/binding"
 )
 
-const acceptHeader = "Accept"
+const (
+	acceptHeader = "Accept"
+
+	// RequestIDHeader is the request id of the request and the response.
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey is the request id in gin context, set by the router.
+	RequestIDKey = "capitan_request_id"
+)
+
+// failResponse is pb.APIResponse with the request id in json, protobuf has the header only.
+type failResponse struct {
+	Code      int64  `json:"code,omitempty"`
+	Response  string `json:"response,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// RequestID is empty if the request is not through the router.
+func RequestID(c *gin.Context) string {
+	return c.GetString(RequestIDKey)
+}
 
 func Fail(c *gin.Context, code int, err error) {
-	fn := c.JSON
-	if c.Request.Header.Get(acceptHeader) == binding.MIMEPROTOBUF {
-		fn = c.ProtoBuf
-	}
 	resp := &pb.APIResponse{}
 	switch v := err.(type) {
 	case *repo.Error:
@@ -31,7 +46,19 @@ func Fail(c *gin.Context, code int, err error) {
 		resp.Code = -1
 		resp.Response = v.Error()
 	}
-	fn(code, resp)
+	if err != nil {
+		// logged by the access log
+		_ = c.Error(err)
+	}
+	if c.Request.Header.Get(acceptHeader) == binding.MIMEPROTOBUF {
+		c.ProtoBuf(code, resp)
+	} else {
+		c.JSON(code, &failResponse{
+			Code:      resp.GetCode(),
+			Response:  resp.GetResponse(),
+			RequestID: RequestID(c),
+		})
+	}
 	c.Abort()
 }
 
@@ -0,0 +1,79 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	jwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/chindada/capitan/internal/controller/http/resp"
+	gRPCClient "github.com/chindada/capitan/internal/usecases/grpc/client"
+	"github.com/chindada/leopard/pkg/log"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// validRequestID keeps the logs safe from the ids given by clients.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// requestID takes X-Request-ID of the client or the proxy, or generates one.
+// The id is in the response header, the errors of resp.Fail and the rpcs to panther.
+func requestID(c *gin.Context) {
+	id := c.GetHeader(resp.RequestIDHeader)
+	if !validRequestID.MatchString(id) {
+		id = uuid.NewString()
+	}
+	c.Set(resp.RequestIDKey, id)
+	c.Header(resp.RequestIDHeader, id)
+	c.Request = c.Request.WithContext(gRPCClient.WithRequestID(c.Request.Context(), id))
+	c.Next()
+}
+
+// accessLog logs each request after served as key=value, the formatters of leopard log drop the fields of logrus.
+func accessLog(logger *log.Log) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		user, _ := jwt.ExtractClaims(c)["username"].(string)
+		b := &strings.Builder{}
+		for _, kv := range [][2]string{
+			{"request_id", resp.RequestID(c)},
+			{"user", user},
+			{"method", c.Request.Method},
+			{"route", route},
+			{"path", c.Request.URL.Path},
+			{"status", strconv.Itoa(c.Writer.Status())},
+			{"latency", time.Since(start).Round(time.Microsecond).String()},
+			{"bytes", strconv.Itoa(max(c.Writer.Size(), 0))},
+			{"ip", c.ClientIP()},
+			{"error", strings.Join(c.Errors.Errors(), "; ")},
+		} {
+			if kv[1] == "" {
+				continue
+			}
+			fmt.Fprintf(b, " %s=%s", kv[0], logValue(kv[1]))
+		}
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			logger.Errorf("access%s", b)
+			return
+		}
+		logger.Infof("access%s", b)
+	}
+}
+
+// logValue is quoted if it has any space, quote or control char.
+func logValue(v string) string {
+	if strings.ContainsFunc(v, func(r rune) bool {
+		return r <= ' ' || r == '"' || r == '=' || !strconv.IsPrint(r)
+	}) {
+		return strconv.Quote(v)
+	}
+	return v
+}
@@ -0,0 +1,151 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/chindada/capitan/internal/controller/http/resp"
+	"github.com/chindada/capitan/internal/controller/http/ws"
+	"github.com/chindada/panther/golang/pb"
+	"github.com/gorilla/websocket"
+)
+
+// maintenanceRetryAfter is the Retry-After seconds of the responses during maintenance.
+const maintenanceRetryAfter = 30
+
+var errMaintenance = errors.New("maintenance")
+
+// Gate is in front of the gin engine and survives the engine rebuilt after restore.
+// In maintenance every request gets 503, Drain waits the in-flight requests and closes the websockets.
+// Shutdown is Drain with the websockets told going away, never resumed.
+type Gate struct {
+	lock     sync.Mutex
+	idle     *sync.Cond
+	handler  http.Handler
+	probe    http.Handler
+	draining bool
+	inflight int
+	sockets  map[*http.Request]context.CancelCauseFunc
+}
+
+func NewGate() *Gate {
+	g := &Gate{
+		sockets: make(map[*http.Request]context.CancelCauseFunc),
+	}
+	g.idle = sync.NewCond(&g.lock)
+	return g
+}
+
+// SetHandler replaces the handler behind the gate.
+func (g *Gate) SetHandler(handler http.Handler) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.handler = handler
+}
+
+// SetProbe serves the probes of NewProbeHandler in front of the gate.
+func (g *Gate) SetProbe(probe http.Handler) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.probe = probe
+}
+
+func (g *Gate) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.lock.Lock()
+	if probe := g.probe; probe != nil && isProbe(r) {
+		g.lock.Unlock()
+		probe.ServeHTTP(w, r)
+		return
+	}
+	if g.draining || g.handler == nil {
+		g.lock.Unlock()
+		writeMaintenance(w)
+		return
+	}
+	handler := g.handler
+	g.inflight++
+	if websocket.IsWebSocketUpgrade(r) {
+		ctx, cancel := context.WithCancelCause(r.Context())
+		r = r.WithContext(ctx)
+		g.sockets[r] = cancel
+	}
+	g.lock.Unlock()
+
+	defer func() {
+		g.lock.Lock()
+		defer g.lock.Unlock()
+		if cancel, ok := g.sockets[r]; ok {
+			cancel(nil)
+			delete(g.sockets, r)
+		}
+		g.inflight--
+		if g.inflight == 0 {
+			g.idle.Broadcast()
+		}
+	}()
+	handler.ServeHTTP(w, r)
+}
+
+// Drain rejects new requests, closes the websockets and waits the in-flight requests until ctx done.
+func (g *Gate) Drain(ctx context.Context) error {
+	return g.drain(ctx, errMaintenance)
+}
+
+// Shutdown drains as Drain, the websockets are closed as going away.
+func (g *Gate) Shutdown(ctx context.Context) error {
+	return g.drain(ctx, ws.ErrShutdown)
+}
+
+func (g *Gate) drain(ctx context.Context, cause error) error {
+	g.lock.Lock()
+	g.draining = true
+	for _, cancel := range g.sockets {
+		cancel(cause)
+	}
+	g.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.lock.Lock()
+		for g.inflight > 0 {
+			g.idle.Wait()
+		}
+		g.lock.Unlock()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Resume serves the requests again.
+func (g *Gate) Resume() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.draining = false
+}
+
+// InMaintenance is true between Drain and Resume.
+func (g *Gate) InMaintenance() bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return g.draining
+}
+
+func writeMaintenance(w http.ResponseWriter) {
+	body, _ := json.Marshal(&pb.APIResponse{
+		Code:     resp.ErrMaintenance.Code,
+		Response: resp.ErrMaintenance.Message,
+	})
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Retry-After", strconv.Itoa(maintenanceRetryAfter))
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,76 @@
+package router
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"time"
+
+	"github.com/chindada/capitan/internal/controller/http/resp"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	metricsPath = "/metrics"
+	// forwardedForHeader is set by the proxy on every request.
+	forwardedForHeader = "X-Forwarded-For"
+	// unmatchedRoute labels the requests of no route, keeps the paths scanned out of the labels.
+	unmatchedRoute = "unmatched"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "capitan",
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Requests by route, method and status.",
+	}, []string{"route", "method", "status"})
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "capitan",
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the requests by route and method, websockets excluded.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+)
+
+// observeHTTP counts every request, the latency of a websocket is its lifetime so not observed.
+func observeHTTP(c *gin.Context) {
+	start := time.Now()
+	c.Next()
+	route := c.FullPath()
+	if route == "" {
+		route = unmatchedRoute
+	}
+	httpRequests.WithLabelValues(route, c.Request.Method, strconv.Itoa(c.Writer.Status())).Inc()
+	if !c.IsWebsocket() {
+		httpDuration.WithLabelValues(route, c.Request.Method).Observe(time.Since(start).Seconds())
+	}
+}
+
+// metricsGuard allows the bearer token, or the remote address in allow if not through the proxy,
+// the proxy is on loopback so its address tells nothing of the client.
+func metricsGuard(token string, allow []netip.Prefix) gin.HandlerFunc {
+	bearer := []byte("Bearer " + token)
+	return func(c *gin.Context) {
+		if token != "" && subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), bearer) == 1 {
+			return
+		}
+		if c.GetHeader(forwardedForHeader) != "" {
+			resp.Fail(c, http.StatusForbidden, resp.ErrPermissionDenied)
+			return
+		}
+		if addr, err := netip.ParseAddr(c.RemoteIP()); err == nil {
+			addr = addr.Unmap()
+			for _, p := range allow {
+				if p.Contains(addr) {
+					return
+				}
+			}
+		}
+		resp.Fail(c, http.StatusForbidden, resp.ErrPermissionDenied)
+	}
+}
@@ -0,0 +1,66 @@
+package router
+
+import (
+	"net/http"
+	"net/netip"
+
+	"github.com/chindada/capitan/internal/controller/http/resp"
+	"github.com/chindada/capitan/internal/usecases"
+	"github.com/chindada/capitan/internal/usecases/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	healthzPath = prefix + "/healthz"
+	readyzPath  = prefix + "/readyz"
+)
+
+type probeRoutes struct {
+	health usecases.Health
+}
+
+// NewProbeHandler serves the liveness and readiness without auth, put before the gate so also in maintenance.
+// The metrics are also served, allowed by metricsToken or the remote address in metricsAllow.
+func NewProbeHandler(health usecases.Health, metricsToken string, metricsAllow []netip.Prefix) http.Handler {
+	r := &probeRoutes{health}
+	g := gin.New()
+	g.Use(gin.Recovery())
+	g.GET(healthzPath, r.healthz)
+	g.GET(readyzPath, r.readyz)
+	g.GET(metricsPath, metricsGuard(metricsToken, metricsAllow), gin.WrapH(promhttp.Handler()))
+	return g
+}
+
+// isProbe is true for the paths of NewProbeHandler.
+func isProbe(req *http.Request) bool {
+	return req.URL.Path == healthzPath || req.URL.Path == readyzPath || req.URL.Path == metricsPath
+}
+
+// healthz -.
+//
+//	@Tags		Health
+//	@Summary	Liveness, up as long as the process serves
+//	@Produce	application/json
+//	@Success	200	{object}	entity.Liveness
+//	@Router		/api/capitan/healthz [get]
+func (r *probeRoutes) healthz(c *gin.Context) {
+	resp.Success(c, http.StatusOK, r.health.Live())
+}
+
+// readyz -.
+//
+//	@Tags		Health
+//	@Summary	Readiness with the status of each component, 503 if any down
+//	@Produce	application/json
+//	@Success	200	{object}	entity.Readiness
+//	@Failure	503	{object}	entity.Readiness
+//	@Router		/api/capitan/readyz [get]
+func (r *probeRoutes) readyz(c *gin.Context) {
+	ready := r.health.Ready(c)
+	code := http.StatusOK
+	if ready.Status == entity.HealthStatusDown {
+		code = http.StatusServiceUnavailable
+	}
+	resp.Success(c, code, ready)
+}
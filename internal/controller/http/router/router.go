@@ -14,12 +14,15 @@ import (
 	"github.com/chindada/capitan/internal/controller/http/ws"
 	"github.com/chindada/capitan/internal/usecases"
 	"github.com/chindada/capitan/internal/version"
+	"github.com/chindada/leopard/pkg/log"
 	"github.com/gin-gonic/gin"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
 const (
+	serviceName = "capitan"
+
 	prefix = "/api/capitan"
 	wsPath = "/ws/capitan"
 )
@@ -28,10 +31,11 @@ var swagHandler gin.HandlerFunc
 
 // Router -.
 type Router struct {
-	rootHandler *gin.Engine
-	v1WSGroup   *gin.RouterGroup
-	v1Group     *gin.RouterGroup
-	jwtHandler  *jwt.GinJWTMiddleware
+	rootHandler  *gin.Engine
+	v1WSGroup    *gin.RouterGroup
+	v1Group      *gin.RouterGroup
+	v1AdminGroup *gin.RouterGroup
+	jwtHandler   *jwt.GinJWTMiddleware
 }
 
 // NewRouter -.
@@ -44,8 +48,9 @@ type Router struct {
 //	@name						Authorization
 func NewRouter(system usecases.System) *Router {
 	g := gin.New()
-	g.Use(gin.Recovery())
-	g.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	// the use cases given *gin.Context get the span of the request
+	g.ContextWithFallback = true
+	g.Use(requestID, accessLog(log.Get()), gin.Recovery(), otelgin.Middleware(serviceName), observeHTTP)
 
 	jwtHandler, err := auth.NewAuthMiddleware(system, time.Hour*8)
 	if err != nil {
@@ -86,19 +91,21 @@ func NewRouter(system usecases.System) *Router {
 	v1Private := g.Group(v1Prefix)
 	v1WSGroup.Use(jwtHandler.MiddlewareFunc())
 	v1Private.Use(jwtHandler.MiddlewareFunc())
+	v1Admin := v1Private.Group("", auth.NewAdminMiddleware(system))
 
 	v1.NewUserRoutes(v1Public, v1Private, jwtHandler, system)
 
 	return &Router{
-		rootHandler: g,
-		v1WSGroup:   v1WSGroup,
-		v1Group:     v1Private,
-		jwtHandler:  jwtHandler,
+		rootHandler:  g,
+		v1WSGroup:    v1WSGroup,
+		v1Group:      v1Private,
+		v1AdminGroup: v1Admin,
+		jwtHandler:   jwtHandler,
 	}
 }
 
 func (r *Router) AddV1BasicRoutes(basic usecases.Basic) *Router {
-	v1.NewBasicRoutes(r.v1Group, basic)
+	v1.NewBasicRoutes(r.v1Group, r.v1AdminGroup, r.v1WSGroup, basic)
 	return r
 }
 
@@ -107,8 +114,23 @@ func (r *Router) AddV1StreamRoutes(stream usecases.Stream) *Router {
 	return r
 }
 
-func (r *Router) AddV1SystemRoutes() *Router {
-	v1.NewSystemRoutes(r.v1Group)
+func (r *Router) AddV1SystemRoutes(backup usecases.Backup) *Router {
+	v1.NewSystemRoutes(r.v1Group, r.v1AdminGroup, r.v1WSGroup, backup)
+	return r
+}
+
+func (r *Router) AddV1TransferRoutes(transfer usecases.Transfer) *Router {
+	v1.NewTransferRoutes(r.v1AdminGroup, transfer)
+	return r
+}
+
+func (r *Router) AddV1SettingRoutes(settings usecases.Settings) *Router {
+	v1.NewSettingRoutes(r.v1AdminGroup, settings)
+	return r
+}
+
+func (r *Router) AddV1ConfigRoutes(reloader usecases.Reloader) *Router {
+	v1.NewConfigRoutes(r.v1AdminGroup, reloader)
 	return r
 }
 
@@ -1,30 +1,80 @@
 package v1
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/chindada/capitan/internal/controller/http/resp"
+	"github.com/chindada/capitan/internal/controller/http/ws"
 	"github.com/chindada/capitan/internal/usecases"
+	"github.com/chindada/capitan/internal/usecases/entity"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 type basicRoutes struct {
 	t usecases.Basic
 }
 
-func NewBasicRoutes(handler *gin.RouterGroup, t usecases.Basic) {
+// defaultHistoryYears is the years of price series before to if from is not given.
+const defaultHistoryYears = 1
+
+type priceSeriesQuery struct {
+	Kind string `form:"kind"`
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+type optionChainQuery struct {
+	Underlying string `form:"underlying"`
+	Month      string `form:"month"`
+}
+
+func NewBasicRoutes(handler, adminHandler, wsHandler *gin.RouterGroup, t usecases.Basic) {
 	r := &basicRoutes{t}
 
 	h := handler.Group("/basic")
 	{
 		h.GET("/stocks", r.getStocks)
+		h.GET("/futures", r.getFutures)
+		h.GET("/options", r.getOptions)
+		h.GET("/options/chain", r.getOptionChain)
+		h.GET("/refresh", r.getRefreshStatus)
+		h.GET("/history/:code", r.getPriceSeries)
+	}
+
+	a := adminHandler.Group("/basic")
+	{
+		a.POST("/refresh", r.triggerRefresh)
+		a.GET("/refresh/summary", r.getRefreshSummary)
+	}
+
+	w := wsHandler.Group("/basic")
+	{
+		w.GET("/options/chain", r.streamOptionChain)
+	}
+}
+
+func bindOptionChainQuery(c *gin.Context) (*optionChainQuery, error) {
+	q := optionChainQuery{}
+	if err := c.ShouldBindQuery(&q); err != nil {
+		return nil, err
 	}
+	if q.Underlying == "" {
+		return nil, resp.ErrUnderlyingRequired
+	}
+	if q.Month == "" {
+		return nil, resp.ErrMonthRequired
+	}
+	return &q, nil
 }
 
 // getStocks -.
 //
 //	@Tags		Basic V1
-//	@Summary	Get stocks
+//	@Summary	Get stocks, the active ones in database if upstream unavailable
 //	@security	JWT
 //	@Accept		application/json
 //	@Produce	application/json
@@ -40,3 +90,233 @@ func (r *basicRoutes) getStocks(c *gin.Context) {
 	}
 	resp.Success(c, http.StatusOK, stocks)
 }
+
+// getFutures -.
+//
+//	@Tags		Basic V1
+//	@Summary	Get futures not yet expired
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@param		underlying_kind	query		string	false	"underlying_kind"
+//	@param		delivery_month	query		string	false	"delivery_month"
+//	@param		category		query		string	false	"category"
+//	@Success	200				{object}	pb.FutureDetailList
+//	@Failure	400				{object}	pb.APIResponse
+//	@Failure	500				{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/basic/futures [get]
+func (r *basicRoutes) getFutures(c *gin.Context) {
+	filter := entity.ContractFilter{}
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		resp.Fail(c, http.StatusBadRequest, err)
+		return
+	}
+	futures, err := r.t.GetFutureDetail(c, &filter)
+	if err != nil {
+		resp.Fail(c, http.StatusInternalServerError, err)
+		return
+	}
+	resp.Success(c, http.StatusOK, futures)
+}
+
+// getOptions -.
+//
+//	@Tags		Basic V1
+//	@Summary	Get options not yet expired
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@param		underlying_kind	query		string	false	"underlying_kind"
+//	@param		delivery_month	query		string	false	"delivery_month"
+//	@param		category		query		string	false	"category"
+//	@param		option_right	query		string	false	"option_right"
+//	@Success	200				{object}	pb.OptionDetailList
+//	@Failure	400				{object}	pb.APIResponse
+//	@Failure	500				{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/basic/options [get]
+func (r *basicRoutes) getOptions(c *gin.Context) {
+	filter := entity.ContractFilter{}
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		resp.Fail(c, http.StatusBadRequest, err)
+		return
+	}
+	options, err := r.t.GetOptionDetail(c, &filter)
+	if err != nil {
+		resp.Fail(c, http.StatusInternalServerError, err)
+		return
+	}
+	resp.Success(c, http.StatusOK, options)
+}
+
+// getOptionChain -.
+//
+//	@Tags		Basic V1
+//	@Summary	Get option chain
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@param		underlying	query		string	true	"underlying, e.g. TXO"
+//	@param		month		query		string	true	"delivery month, e.g. 202507"
+//	@Success	200			{object}	entity.OptionChain
+//	@Failure	400			{object}	pb.APIResponse
+//	@Failure	500			{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/basic/options/chain [get]
+func (r *basicRoutes) getOptionChain(c *gin.Context) {
+	q, err := bindOptionChainQuery(c)
+	if err != nil {
+		resp.Fail(c, http.StatusBadRequest, err)
+		return
+	}
+	chain, err := r.t.GetOptionChain(c, q.Underlying, q.Month)
+	if err != nil {
+		resp.Fail(c, http.StatusInternalServerError, err)
+		return
+	}
+	resp.Success(c, http.StatusOK, chain)
+}
+
+// streamOptionChain pushes the whole chain in json text message whenever last price changed.
+func (r *basicRoutes) streamOptionChain(c *gin.Context) {
+	q, err := bindOptionChainQuery(c)
+	if err != nil {
+		resp.Fail(c, http.StatusBadRequest, err)
+		return
+	}
+	chainChan, err := r.t.SubscribeOptionChain(c.Request.Context(), q.Underlying, q.Month)
+	if err != nil {
+		if errors.Is(err, usecases.ErrUpstreamUnavailable) {
+			resp.Fail(c, http.StatusServiceUnavailable, err)
+			return
+		}
+		resp.Fail(c, http.StatusInternalServerError, err)
+		return
+	}
+	forwardChan := make(chan []byte)
+	w, err := ws.New(c, forwardChan)
+	if err != nil {
+		resp.Fail(c, http.StatusInternalServerError, err)
+		return
+	}
+	go func() {
+		for range forwardChan {
+			continue
+		}
+	}()
+	go func() {
+		for chain := range chainChan {
+			data, mErr := json.Marshal(chain)
+			if mErr != nil {
+				continue
+			}
+			w.WriteTextMessage(data)
+		}
+	}()
+	w.ReadMessage()
+}
+
+// getRefreshStatus -.
+//
+//	@Tags		Basic V1
+//	@Summary	Get instrument refresh status
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@Success	200	{object}	entity.RefreshStatus
+//	@Router		/api/capitan/v1/basic/refresh [get]
+func (r *basicRoutes) getRefreshStatus(c *gin.Context) {
+	resp.Success(c, http.StatusOK, r.t.GetRefreshStatus())
+}
+
+// triggerRefresh -.
+//
+//	@Tags		Basic V1
+//	@Summary	Trigger instrument refresh, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@Success	202	{object}	emptypb.Empty
+//	@Failure	403	{object}	pb.APIResponse
+//	@Failure	409	{object}	pb.APIResponse
+//	@Failure	503	{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/basic/refresh [post]
+func (r *basicRoutes) triggerRefresh(c *gin.Context) {
+	if err := r.t.TriggerRefresh(); err != nil {
+		if errors.Is(err, usecases.ErrRefreshInProgress) {
+			resp.Fail(c, http.StatusConflict, err)
+			return
+		}
+		if errors.Is(err, usecases.ErrUpstreamUnavailable) {
+			resp.Fail(c, http.StatusServiceUnavailable, err)
+			return
+		}
+		resp.Fail(c, http.StatusInternalServerError, err)
+		return
+	}
+	resp.Success(c, http.StatusAccepted, &emptypb.Empty{})
+}
+
+// getRefreshSummary -.
+//
+//	@Tags		Basic V1
+//	@Summary	Get added, removed and changed instruments of the latest refresh, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@Success	200	{object}	entity.RefreshSummary
+//	@Failure	403	{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/basic/refresh/summary [get]
+func (r *basicRoutes) getRefreshSummary(c *gin.Context) {
+	resp.Success(c, http.StatusOK, r.t.GetRefreshSummary())
+}
+
+// getPriceSeries -.
+//
+//	@Tags		Basic V1
+//	@Summary	Get daily reference price series of code of kind
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@param		code	path		string	true	"code"
+//	@param		kind	query		string	true	"stock, future or option"
+//	@param		from	query		string	false	"from date, 2006-01-02, default one year ago"
+//	@param		to		query		string	false	"to date, 2006-01-02, default today"
+//	@Success	200		{object}	entity.PriceSeries
+//	@Failure	400		{object}	pb.APIResponse
+//	@Failure	500		{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/basic/history/{code} [get]
+func (r *basicRoutes) getPriceSeries(c *gin.Context) {
+	q := priceSeriesQuery{}
+	if err := c.ShouldBindQuery(&q); err != nil {
+		resp.Fail(c, http.StatusBadRequest, err)
+		return
+	}
+	kind := entity.InstrumentKind(q.Kind)
+	if !kind.Valid() {
+		resp.Fail(c, http.StatusBadRequest, resp.ErrKindInvalid)
+		return
+	}
+	to := time.Now()
+	if q.To != "" {
+		t, err := time.ParseInLocation(time.DateOnly, q.To, time.Local)
+		if err != nil {
+			resp.Fail(c, http.StatusBadRequest, resp.ErrDateFormatInvalid)
+			return
+		}
+		to = t
+	}
+	from := to.AddDate(-defaultHistoryYears, 0, 0)
+	if q.From != "" {
+		t, err := time.ParseInLocation(time.DateOnly, q.From, time.Local)
+		if err != nil {
+			resp.Fail(c, http.StatusBadRequest, resp.ErrDateFormatInvalid)
+			return
+		}
+		from = t
+	}
+	series, err := r.t.GetPriceSeries(c, kind, c.Param("code"), from, to)
+	if err != nil {
+		resp.Fail(c, http.StatusInternalServerError, err)
+		return
+	}
+	resp.Success(c, http.StatusOK, series)
+}
@@ -0,0 +1,48 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/chindada/capitan/internal/controller/http/resp"
+	"github.com/chindada/capitan/internal/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type configRoutes struct {
+	reloader usecases.Reloader
+}
+
+func NewConfigRoutes(adminHandler *gin.RouterGroup, reloader usecases.Reloader) {
+	r := &configRoutes{reloader}
+
+	a := adminHandler.Group("/system/config")
+	{
+		a.POST("/reload", r.reloadConfig)
+	}
+}
+
+// reloadConfig -.
+//
+//	@Tags		Config V1
+//	@Summary	Reload config as SIGHUP, the keys not applied live are listed as restart required, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@Success	200	{object}	entity.ConfigReload
+//	@Failure	400	{object}	pb.APIResponse
+//	@Failure	403	{object}	pb.APIResponse
+//	@Failure	500	{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/config/reload [post]
+func (r *configRoutes) reloadConfig(c *gin.Context) {
+	result, err := r.reloader.ReloadConfig(c)
+	if err != nil {
+		if errors.Is(err, usecases.ErrConfigInvalid) {
+			resp.Fail(c, http.StatusBadRequest, err)
+			return
+		}
+		resp.Fail(c, http.StatusInternalServerError, err)
+		return
+	}
+	resp.Success(c, http.StatusOK, result)
+}
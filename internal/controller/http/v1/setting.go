@@ -0,0 +1,110 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/chindada/capitan/internal/controller/http/resp"
+	"github.com/chindada/capitan/internal/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type settingRoutes struct {
+	settings usecases.Settings
+}
+
+func NewSettingRoutes(adminHandler *gin.RouterGroup, settings usecases.Settings) {
+	r := &settingRoutes{settings}
+
+	a := adminHandler.Group("/system/settings")
+	{
+		a.GET("", r.listSettings)
+		a.GET("/:name", r.getSetting)
+		a.PUT("/:name", r.updateSetting)
+	}
+}
+
+func (r *settingRoutes) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrSettingNotFound):
+		resp.Fail(c, http.StatusNotFound, err)
+	case errors.Is(err, usecases.ErrSettingInvalid):
+		resp.Fail(c, http.StatusBadRequest, err)
+	case errors.Is(err, usecases.ErrSettingReadOnly):
+		resp.Fail(c, http.StatusForbidden, err)
+	default:
+		resp.Fail(c, http.StatusInternalServerError, err)
+	}
+}
+
+// listSettings -.
+//
+//	@Tags		Setting V1
+//	@Summary	List all known settings without secrets, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@Success	200	{array}		entity.Setting
+//	@Failure	403	{object}	pb.APIResponse
+//	@Failure	500	{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/settings [get]
+func (r *settingRoutes) listSettings(c *gin.Context) {
+	list, err := r.settings.ListSettings(c)
+	if err != nil {
+		r.fail(c, err)
+		return
+	}
+	resp.Success(c, http.StatusOK, list)
+}
+
+// getSetting -.
+//
+//	@Tags		Setting V1
+//	@Summary	Get setting by name without secrets, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@param		name	path		string	true	"name"
+//	@Success	200		{object}	entity.Setting
+//	@Failure	403		{object}	pb.APIResponse
+//	@Failure	404		{object}	pb.APIResponse
+//	@Failure	500		{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/settings/{name} [get]
+func (r *settingRoutes) getSetting(c *gin.Context) {
+	setting, err := r.settings.GetSetting(c, c.Param("name"))
+	if err != nil {
+		r.fail(c, err)
+		return
+	}
+	resp.Success(c, http.StatusOK, setting)
+}
+
+// updateSetting -.
+//
+//	@Tags		Setting V1
+//	@Summary	Replace setting value, empty secrets keep the saved ones, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@param		name	path		string	true	"name"
+//	@param		body	body		object	true	"value"
+//	@Success	200		{object}	entity.Setting
+//	@Failure	400		{object}	pb.APIResponse
+//	@Failure	403		{object}	pb.APIResponse
+//	@Failure	404		{object}	pb.APIResponse
+//	@Failure	500		{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/settings/{name} [put]
+func (r *settingRoutes) updateSetting(c *gin.Context) {
+	value := json.RawMessage{}
+	if err := c.ShouldBindJSON(&value); err != nil {
+		resp.Fail(c, http.StatusBadRequest, err)
+		return
+	}
+	setting, err := r.settings.UpdateSetting(c, c.Param("name"), value)
+	if err != nil {
+		r.fail(c, err)
+		return
+	}
+	resp.Success(c, http.StatusOK, setting)
+}
@@ -1,14 +1,18 @@
 package v1
 
 import (
-	"fmt"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
 	"net/http"
-	"os"
-	"path/filepath"
-	"runtime"
-	"syscall"
+	"strconv"
+	"time"
 
 	"github.com/chindada/capitan/internal/controller/http/resp"
+	"github.com/chindada/capitan/internal/controller/http/ws"
+	"github.com/chindada/capitan/internal/usecases"
+	"github.com/chindada/capitan/internal/usecases/entity"
 	"github.com/chindada/panther/golang/pb"
 	"github.com/chindada/panther/pkg/launcher"
 	"github.com/gin-gonic/gin"
@@ -16,54 +20,258 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-type systemRoutes struct{}
+type systemRoutes struct {
+	backup usecases.Backup
+}
 
-func NewSystemRoutes(handler *gin.RouterGroup) {
-	r := &systemRoutes{}
+func NewSystemRoutes(handler, adminHandler, wsHandler *gin.RouterGroup, backup usecases.Backup) {
+	r := &systemRoutes{backup}
 	base := "/system"
 
 	h := handler.Group(base)
 	{
 		h.GET("/backup", r.listBackup)
 		h.PUT("/backup", r.createBackup)
-		h.POST("/backup", r.restoreBackup)
-		h.DELETE("/backup", r.deleteBackup)
-		h.GET("/backup/download", r.downloadBackup)
-		h.POST("/backup/upload", r.uploadBackup)
+		h.GET("/backup/jobs", r.listBackupJobs)
+		h.GET("/backup/jobs/:id", r.getBackupJob)
+	}
+
+	a := adminHandler.Group(base)
+	{
+		a.GET("/backup/schedule", r.getBackupSchedule)
+		a.PUT("/backup/schedule", r.updateBackupSchedule)
+		a.GET("/backup/targets", r.getBackupTargets)
+		a.PUT("/backup/targets", r.updateBackupTargets)
+		a.POST("/backup/upload", r.uploadBackup)
+		a.POST("/backup/upload/sessions", r.createUploadSession)
+		a.GET("/backup/upload/sessions/:id", r.getUploadSession)
+		a.PATCH("/backup/upload/sessions/:id", r.appendUploadSession)
+		a.DELETE("/backup/upload/sessions/:id", r.deleteUploadSession)
+		a.GET("/backup/targets/:target/backups", r.listRemoteBackup)
+		a.POST("/backup/targets/:target/restore", r.restoreRemoteBackup)
+		a.POST("/backup", r.restoreBackup)
+		a.DELETE("/backup", r.deleteBackup)
+		a.GET("/backup/download", r.downloadBackup)
+		a.POST("/backup/verify", r.verifyBackup)
+	}
+
+	w := wsHandler.Group(base)
+	{
+		w.GET("/backup/jobs/:id", r.streamBackupJob)
+	}
+}
+
+func (r *systemRoutes) failJob(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrBackupNotFound),
+		errors.Is(err, usecases.ErrJobNotFound),
+		errors.Is(err, usecases.ErrBackupTargetNotFound),
+		errors.Is(err, usecases.ErrUploadNotFound):
+		resp.Fail(c, http.StatusNotFound, err)
+	case errors.Is(err, usecases.ErrRestoreInProgress),
+		errors.Is(err, usecases.ErrUploadOffsetMismatch):
+		resp.Fail(c, http.StatusConflict, err)
+	case errors.Is(err, usecases.ErrUploadTooLarge):
+		resp.Fail(c, http.StatusRequestEntityTooLarge, err)
+	case errors.Is(err, usecases.ErrBackupCorrupted),
+		errors.Is(err, usecases.ErrBackupSchemaMismatch),
+		errors.Is(err, usecases.ErrBackupManifestMissing),
+		errors.Is(err, usecases.ErrBackupKeyRequired),
+		errors.Is(err, usecases.ErrBackupDecrypt):
+		resp.Fail(c, http.StatusBadRequest, err)
+	default:
+		resp.Fail(c, http.StatusInternalServerError, err)
+	}
+}
+
+// noDeadline clears the read and write timeouts of the server, a transfer lasts as long as the network needs.
+func noDeadline(c *gin.Context) {
+	rc := http.NewResponseController(c.Writer)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+}
+
+// getBackupSchedule -.
+//
+//	@Tags		System V1
+//	@Summary	Get backup schedule and the last result, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@Success	200	{object}	entity.BackupScheduleStatus
+//	@Failure	403	{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/backup/schedule [get]
+func (r *systemRoutes) getBackupSchedule(c *gin.Context) {
+	resp.Success(c, http.StatusOK, r.backup.GetScheduleStatus())
+}
+
+// updateBackupSchedule -.
+//
+//	@Tags		System V1
+//	@Summary	Update backup schedule, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@param		body	body		entity.BackupSchedule	true	"Body"
+//	@Success	200		{object}	entity.BackupScheduleStatus
+//	@Failure	400		{object}	pb.APIResponse
+//	@Failure	403		{object}	pb.APIResponse
+//	@Failure	409		{object}	pb.APIResponse
+//	@Failure	500		{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/backup/schedule [put]
+func (r *systemRoutes) updateBackupSchedule(c *gin.Context) {
+	schedule := &entity.BackupSchedule{}
+	if err := c.ShouldBindJSON(schedule); err != nil {
+		resp.Fail(c, http.StatusBadRequest, err)
+		return
+	}
+	if err := r.backup.UpdateSchedule(c, schedule); err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrBackupScheduleInvalid):
+			resp.Fail(c, http.StatusBadRequest, err)
+		case errors.Is(err, usecases.ErrRestoreInProgress):
+			resp.Fail(c, http.StatusConflict, err)
+		default:
+			resp.Fail(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	resp.Success(c, http.StatusOK, r.backup.GetScheduleStatus())
+}
+
+// getBackupTargets -.
+//
+//	@Tags		System V1
+//	@Summary	Get backup targets without secrets and the last replication, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@Success	200	{array}		entity.BackupTargetStatus
+//	@Failure	403	{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/backup/targets [get]
+func (r *systemRoutes) getBackupTargets(c *gin.Context) {
+	resp.Success(c, http.StatusOK, r.backup.GetTargets())
+}
+
+// updateBackupTargets -.
+//
+//	@Tags		System V1
+//	@Summary	Replace backup targets, empty secrets keep the saved ones, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@param		body	body		[]entity.BackupTarget	true	"Body"
+//	@Success	200		{array}		entity.BackupTargetStatus
+//	@Failure	400		{object}	pb.APIResponse
+//	@Failure	403		{object}	pb.APIResponse
+//	@Failure	409		{object}	pb.APIResponse
+//	@Failure	500		{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/backup/targets [put]
+func (r *systemRoutes) updateBackupTargets(c *gin.Context) {
+	targets := []*entity.BackupTarget{}
+	if err := c.ShouldBindJSON(&targets); err != nil {
+		resp.Fail(c, http.StatusBadRequest, err)
+		return
+	}
+	if err := r.backup.UpdateTargets(c, targets); err != nil {
+		switch {
+		case errors.Is(err, usecases.ErrBackupTargetInvalid):
+			resp.Fail(c, http.StatusBadRequest, err)
+		case errors.Is(err, usecases.ErrRestoreInProgress):
+			resp.Fail(c, http.StatusConflict, err)
+		default:
+			resp.Fail(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	resp.Success(c, http.StatusOK, r.backup.GetTargets())
+}
+
+// listRemoteBackup -.
+//
+//	@Tags		System V1
+//	@Summary	List backup on target, newest first, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@param		target	path		string	true	"target"
+//	@Success	200		{array}		entity.RemoteBackup
+//	@Failure	404		{object}	pb.APIResponse
+//	@Failure	500		{object}	pb.APIResponse
+//	@Failure	403		{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/backup/targets/{target}/backups [get]
+func (r *systemRoutes) listRemoteBackup(c *gin.Context) {
+	list, err := r.backup.ListRemoteBackups(c, c.Param("target"))
+	if err != nil {
+		r.failJob(c, err)
+		return
+	}
+	resp.Success(c, http.StatusOK, list)
+}
+
+// restoreRemoteBackup -.
+//
+//	@Tags		System V1
+//	@Summary	Fetch backup from target and restore in background, requests get 503 until restored, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@param		target		path		string	true	"target"
+//	@param		backup-name	header		string	true	"backup-name"
+//	@Success	202			{object}	entity.Job
+//	@Failure	400			{object}	pb.APIResponse
+//	@Failure	404			{object}	pb.APIResponse
+//	@Failure	409			{object}	pb.APIResponse
+//	@Failure	500			{object}	pb.APIResponse
+//	@Failure	403			{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/backup/targets/{target}/restore [post]
+func (r *systemRoutes) restoreRemoteBackup(c *gin.Context) {
+	backupName := c.GetHeader("backup-name")
+	if backupName == "" {
+		resp.Fail(c, http.StatusBadRequest, resp.ErrNameRequired)
+		return
 	}
+	job, err := r.backup.CreateRemoteRestoreJob(c.Param("target"), backupName)
+	if err != nil {
+		r.failJob(c, err)
+		return
+	}
+	resp.Success(c, http.StatusAccepted, job)
 }
 
 // createBackup -.
 //
 //	@Tags		System V1
-//	@Summary	Create backup
+//	@Summary	Create backup in background
 //	@security	JWT
 //	@Accept		application/json
 //	@Produce	application/json
-//	@Success	200	{object}	emptypb.Empty
+//	@Success	202	{object}	entity.Job
 //	@Failure	500	{object}	pb.APIResponse
 //	@Router		/api/capitan/v1/system/backup [put]
 func (r *systemRoutes) createBackup(c *gin.Context) {
-	dbt := launcher.Get()
-	err := dbt.Backup(false)
+	job, err := r.backup.CreateBackupJob()
 	if err != nil {
-		resp.Fail(c, http.StatusInternalServerError, err)
+		r.failJob(c, err)
 		return
 	}
-	resp.Success(c, http.StatusOK, &emptypb.Empty{})
+	resp.Success(c, http.StatusAccepted, job)
 }
 
 // restoreBackup -.
 //
 //	@Tags		System V1
-//	@Summary	Restore backup
+//	@Summary	Restore backup in background, requests get 503 until restored, admin only
 //	@security	JWT
 //	@Accept		application/json
 //	@Produce	application/json
 //	@param		body	body		pb.Backup	true	"Body"
-//	@Success	200		{object}	emptypb.Empty
+//	@Success	202		{object}	entity.Job
 //	@Failure	400		{object}	pb.APIResponse
+//	@Failure	404		{object}	pb.APIResponse
+//	@Failure	409		{object}	pb.APIResponse
 //	@Failure	500		{object}	pb.APIResponse
+//	@Failure	403		{object}	pb.APIResponse
 //	@Router		/api/capitan/v1/system/backup [post]
 func (r *systemRoutes) restoreBackup(c *gin.Context) {
 	backup := &pb.Backup{}
@@ -72,30 +280,16 @@ func (r *systemRoutes) restoreBackup(c *gin.Context) {
 		resp.Fail(c, http.StatusBadRequest, err)
 		return
 	}
-	dbt := launcher.Get()
-	if err = dbt.RestoreDatabase(backup.GetName()); err != nil {
-		resp.Fail(c, http.StatusInternalServerError, err)
+	if backup.GetName() == "" {
+		resp.Fail(c, http.StatusBadRequest, resp.ErrNameRequired)
 		return
 	}
-	p, e := os.FindProcess(os.Getpid())
-	if e != nil {
-		resp.Fail(c, http.StatusInternalServerError, e)
+	job, err := r.backup.CreateRestoreJob(backup.GetName())
+	if err != nil {
+		r.failJob(c, err)
 		return
 	}
-	if runtime.GOOS == "windows" {
-		e = p.Kill()
-		if e != nil {
-			resp.Fail(c, http.StatusInternalServerError, e)
-			return
-		}
-	} else {
-		e = p.Signal(syscall.SIGQUIT)
-		if e != nil {
-			resp.Fail(c, http.StatusInternalServerError, e)
-			return
-		}
-	}
-	resp.Success(c, http.StatusOK, &emptypb.Empty{})
+	resp.Success(c, http.StatusAccepted, job)
 }
 
 // listBackup -.
@@ -131,81 +325,291 @@ func (r *systemRoutes) listBackup(c *gin.Context) {
 // downloadBackup -.
 //
 //	@Tags		System V1
-//	@Summary	Download backup
+//	@Summary	Download backup as zip, range supported unless sealed by the passphrase, admin only
 //	@security	JWT
 //	@Accept		application/json
-//	@Produce	application/json
-//	@param		backup-name	header		string	true	"backup-name"
-//	@Failure	400			{object}	pb.APIResponse
-//	@Failure	500			{object}	pb.APIResponse
+//	@Produce	application/octet-stream
+//	@param		backup-name			header		string	true	"backup-name"
+//	@param		backup-passphrase	header		string	false	"backup-passphrase"
+//	@param		Range				header		string	false	"Range"
+//	@Success	200					{file}		file
+//	@Success	206					{file}		file
+//	@Failure	400					{object}	pb.APIResponse
+//	@Failure	404					{object}	pb.APIResponse
+//	@Failure	409					{object}	pb.APIResponse
+//	@Failure	500					{object}	pb.APIResponse
+//	@Failure	403					{object}	pb.APIResponse
 //	@Router		/api/capitan/v1/system/backup/download [get]
 func (r *systemRoutes) downloadBackup(c *gin.Context) {
+	noDeadline(c)
 	backupName := c.GetHeader("backup-name")
 	if backupName == "" {
 		resp.Fail(c, http.StatusBadRequest, resp.ErrNameRequired)
 		return
 	}
+	archive, err := r.backup.OpenArchive(backupName, c.GetHeader("backup-passphrase"))
+	if err != nil {
+		r.failJob(c, err)
+		return
+	}
+	defer archive.Close()
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": archive.FileName}))
+	c.Header("Content-Type", "application/octet-stream")
+	if archive.Seekable() {
+		c.Header("ETag", archive.ETag)
+		http.ServeContent(c.Writer, c.Request, archive.FileName, archive.ModTime, archive.Reader())
+		return
+	}
+	c.Header("Accept-Ranges", "none")
+	c.Status(http.StatusOK)
+	_, _ = archive.WriteTo(c.Writer)
+}
 
-	dbt := launcher.Get()
-	list, err := dbt.ListBackups()
+// uploadBackup -.
+//
+//	@Tags		System V1
+//	@Summary	Upload backup in one request, the archive is loaded in background, admin only
+//	@security	JWT
+//	@accept		multipart/form-data
+//	@param		file				formData	file	true	"file"
+//	@param		backup-passphrase	header		string	false	"passphrase of the sealed archive"
+//	@Produce	application/json
+//	@Success	202	{object}	entity.Job
+//	@Failure	400	{object}	pb.APIResponse
+//	@Failure	413	{object}	pb.APIResponse
+//	@Failure	500	{object}	pb.APIResponse
+//	@Failure	403	{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/backup/upload [post]
+func (r *systemRoutes) uploadBackup(c *gin.Context) {
+	noDeadline(c)
+	reader, err := c.Request.MultipartReader()
 	if err != nil {
-		resp.Fail(c, http.StatusInternalServerError, err)
+		resp.Fail(c, http.StatusBadRequest, err)
 		return
 	}
-	var filePath string
-	for _, v := range list {
-		if v.Name == backupName {
-			filePath = v.Path
-			break
+	for {
+		part, pErr := reader.NextPart()
+		if errors.Is(pErr, io.EOF) {
+			resp.Fail(c, http.StatusBadRequest, resp.ErrFileRequired)
+			return
+		}
+		if pErr != nil {
+			resp.Fail(c, http.StatusBadRequest, pErr)
+			return
+		}
+		if part.FormName() != "file" {
+			_ = part.Close()
+			continue
 		}
+		job, uErr := r.backup.UploadBackup(part.FileName(), part, c.GetHeader("backup-passphrase"))
+		_ = part.Close()
+		if uErr != nil {
+			r.failJob(c, uErr)
+			return
+		}
+		resp.Success(c, http.StatusAccepted, job)
+		return
+	}
+}
+
+// createUploadSession -.
+//
+//	@Tags		System V1
+//	@Summary	Start a resumable upload, send the chunks by PATCH, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@param		body	body		entity.UploadSession	true	"file_name and size"
+//	@Success	201		{object}	entity.UploadSession
+//	@Failure	400		{object}	pb.APIResponse
+//	@Failure	413		{object}	pb.APIResponse
+//	@Failure	500		{object}	pb.APIResponse
+//	@Failure	403		{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/backup/upload/sessions [post]
+func (r *systemRoutes) createUploadSession(c *gin.Context) {
+	body := &entity.UploadSession{}
+	if err := c.ShouldBindJSON(body); err != nil {
+		resp.Fail(c, http.StatusBadRequest, err)
+		return
 	}
-	if filePath == "" {
-		resp.Fail(c, http.StatusBadRequest, resp.ErrNotFound)
+	session, err := r.backup.CreateUploadSession(body.FileName, body.Size)
+	if err != nil {
+		r.failJob(c, err)
 		return
 	}
-	zipName := fmt.Sprintf("%s.zip", filepath.Base(filePath))
-	zipPath := filepath.Join(filepath.Dir(filePath), zipName)
-	if err = dbt.Zip(zipPath, filePath); err != nil {
-		resp.Fail(c, http.StatusInternalServerError, err)
+	resp.Success(c, http.StatusCreated, session)
+}
+
+// getUploadSession -.
+//
+//	@Tags		System V1
+//	@Summary	Get the received bytes of a resumable upload, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@param		id	path		string	true	"id"
+//	@Success	200	{object}	entity.UploadSession
+//	@Failure	404	{object}	pb.APIResponse
+//	@Failure	403	{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/backup/upload/sessions/{id} [get]
+func (r *systemRoutes) getUploadSession(c *gin.Context) {
+	session, err := r.backup.GetUploadSession(c.Param("id"))
+	if err != nil {
+		r.failJob(c, err)
 		return
 	}
-	c.FileAttachment(
-		zipPath,
-		zipName,
-	)
-	_ = os.Remove(zipPath)
+	resp.Success(c, http.StatusOK, session)
 }
 
-// uploadBackup -.
+// appendUploadSession -.
 //
 //	@Tags		System V1
-//	@Summary	Upload backup
+//	@Summary	Send a chunk at upload-offset, the archive is loaded in background after the last chunk, admin only
 //	@security	JWT
-//	@accept		multipart/form-data
-//	@param		file	formData	file	true	"file"
+//	@Accept		application/octet-stream
 //	@Produce	application/json
+//	@param		id					path		string	true	"id"
+//	@param		upload-offset		header		int		true	"upload-offset"
+//	@param		backup-passphrase	header		string	false	"passphrase of the sealed archive"
+//	@Success	200					{object}	entity.UploadSession
+//	@Failure	400					{object}	pb.APIResponse
+//	@Failure	404					{object}	pb.APIResponse
+//	@Failure	409					{object}	pb.APIResponse
+//	@Failure	413					{object}	pb.APIResponse
+//	@Failure	500					{object}	pb.APIResponse
+//	@Failure	403					{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/backup/upload/sessions/{id} [patch]
+func (r *systemRoutes) appendUploadSession(c *gin.Context) {
+	noDeadline(c)
+	offset, err := strconv.ParseInt(c.GetHeader("upload-offset"), 10, 64)
+	if err != nil || offset < 0 {
+		resp.Fail(c, http.StatusBadRequest, resp.ErrOffsetInvalid)
+		return
+	}
+	session, err := r.backup.AppendUploadSession(c.Param("id"), offset, c.Request.Body, c.GetHeader("backup-passphrase"))
+	if err != nil {
+		r.failJob(c, err)
+		return
+	}
+	resp.Success(c, http.StatusOK, session)
+}
+
+// deleteUploadSession -.
+//
+//	@Tags		System V1
+//	@Summary	Abort a resumable upload, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@param		id	path		string	true	"id"
 //	@Success	200	{object}	emptypb.Empty
-//	@Failure	500	{object}	pb.APIResponse
-//	@Router		/api/capitan/v1/system/backup/upload [post]
-func (r *systemRoutes) uploadBackup(c *gin.Context) {
-	file, err := c.FormFile("file")
+//	@Failure	404	{object}	pb.APIResponse
+//	@Failure	403	{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/backup/upload/sessions/{id} [delete]
+func (r *systemRoutes) deleteUploadSession(c *gin.Context) {
+	if err := r.backup.DeleteUploadSession(c.Param("id")); err != nil {
+		r.failJob(c, err)
+		return
+	}
+	resp.Success(c, http.StatusOK, &emptypb.Empty{})
+}
+
+// verifyBackup -.
+//
+//	@Tags		System V1
+//	@Summary	Verify checksums and dry-run restore into a scratch database in background, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@param		backup-name	header		string	true	"backup-name"
+//	@Success	202			{object}	entity.Job
+//	@Failure	400			{object}	pb.APIResponse
+//	@Failure	404			{object}	pb.APIResponse
+//	@Failure	409			{object}	pb.APIResponse
+//	@Failure	500			{object}	pb.APIResponse
+//	@Failure	403			{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/backup/verify [post]
+func (r *systemRoutes) verifyBackup(c *gin.Context) {
+	backupName := c.GetHeader("backup-name")
+	if backupName == "" {
+		resp.Fail(c, http.StatusBadRequest, resp.ErrNameRequired)
+		return
+	}
+	job, err := r.backup.CreateVerifyJob(backupName)
 	if err != nil {
+		r.failJob(c, err)
 		return
 	}
-	savePath := filepath.Join(os.TempDir(), file.Filename)
-	_ = c.SaveUploadedFile(file, savePath)
-	dbt := launcher.Get()
-	if err = dbt.LoadBackupArchiveFile(savePath); err != nil {
+	resp.Success(c, http.StatusAccepted, job)
+}
+
+// listBackupJobs -.
+//
+//	@Tags		System V1
+//	@Summary	List backup jobs, newest first
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@Success	200	{array}	entity.Job
+//	@Router		/api/capitan/v1/system/backup/jobs [get]
+func (r *systemRoutes) listBackupJobs(c *gin.Context) {
+	resp.Success(c, http.StatusOK, r.backup.ListJobs())
+}
+
+// getBackupJob -.
+//
+//	@Tags		System V1
+//	@Summary	Get backup job
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/json
+//	@param		id	path		string	true	"job id"
+//	@Success	200	{object}	entity.Job
+//	@Failure	404	{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/backup/jobs/{id} [get]
+func (r *systemRoutes) getBackupJob(c *gin.Context) {
+	job, err := r.backup.GetJob(c.Param("id"))
+	if err != nil {
+		r.failJob(c, err)
+		return
+	}
+	resp.Success(c, http.StatusOK, job)
+}
+
+// streamBackupJob pushes the job in json text message on every update until it finished.
+func (r *systemRoutes) streamBackupJob(c *gin.Context) {
+	jobChan, err := r.backup.SubscribeJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		r.failJob(c, err)
+		return
+	}
+	forwardChan := make(chan []byte)
+	w, err := ws.New(c, forwardChan)
+	if err != nil {
 		resp.Fail(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.JSON(http.StatusOK, &emptypb.Empty{})
+	go func() {
+		for range forwardChan {
+			continue
+		}
+	}()
+	go func() {
+		for job := range jobChan {
+			data, mErr := json.Marshal(job)
+			if mErr != nil {
+				continue
+			}
+			w.WriteTextMessage(data)
+		}
+	}()
+	w.ReadMessage()
 }
 
 // deleteBackup -.
 //
 //	@Tags		System V1
-//	@Summary	Delete backup
+//	@Summary	Delete backup, admin only
 //	@security	JWT
 //	@Accept		application/json
 //	@Produce	application/json
@@ -213,6 +617,7 @@ func (r *systemRoutes) uploadBackup(c *gin.Context) {
 //	@Success	200			{object}	emptypb.Empty
 //	@Failure	400			{object}	pb.APIResponse
 //	@Failure	500			{object}	pb.APIResponse
+//	@Failure	403			{object}	pb.APIResponse
 //	@Router		/api/capitan/v1/system/backup [delete]
 func (r *systemRoutes) deleteBackup(c *gin.Context) {
 	backupName := c.GetHeader("backup-name")
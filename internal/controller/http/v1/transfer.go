@@ -0,0 +1,126 @@
+package v1
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/chindada/capitan/internal/controller/http/resp"
+	"github.com/chindada/capitan/internal/usecases"
+	"github.com/chindada/capitan/internal/usecases/entity"
+	"github.com/gin-gonic/gin"
+)
+
+type transferRoutes struct {
+	transfer usecases.Transfer
+}
+
+type transferQuery struct {
+	Format string `form:"format"`
+	Policy string `form:"policy"`
+}
+
+func NewTransferRoutes(adminHandler *gin.RouterGroup, transfer usecases.Transfer) {
+	r := &transferRoutes{transfer}
+
+	a := adminHandler.Group("/system/transfer")
+	{
+		a.GET("/:domain", r.exportDomain)
+		a.POST("/:domain", r.importDomain)
+	}
+}
+
+// bindTransferQuery defaults to json and fail, so nothing is overwritten unless asked.
+func bindTransferQuery(c *gin.Context) (*transferQuery, error) {
+	q := transferQuery{}
+	if err := c.ShouldBindQuery(&q); err != nil {
+		return nil, err
+	}
+	if q.Format == "" {
+		q.Format = string(entity.TransferFormatJSON)
+	}
+	if q.Policy == "" {
+		q.Policy = string(entity.ConflictPolicyFail)
+	}
+	return &q, nil
+}
+
+func (r *transferRoutes) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecases.ErrTransferDomainInvalid),
+		errors.Is(err, usecases.ErrTransferFormatInvalid),
+		errors.Is(err, usecases.ErrConflictPolicyInvalid),
+		errors.Is(err, usecases.ErrTransferFileInvalid):
+		resp.Fail(c, http.StatusBadRequest, err)
+	default:
+		resp.Fail(c, http.StatusInternalServerError, err)
+	}
+}
+
+// exportDomain -.
+//
+//	@Tags		Transfer V1
+//	@Summary	Export users, settings, stocks, futures or options as file, admin only
+//	@security	JWT
+//	@Accept		application/json
+//	@Produce	application/octet-stream
+//	@param		domain	path		string	true	"users, settings, stocks, futures or options"
+//	@param		format	query		string	false	"json (default), ndjson or protobuf, settings have no protobuf"
+//	@Success	200		{file}		file
+//	@Failure	400		{object}	pb.APIResponse
+//	@Failure	403		{object}	pb.APIResponse
+//	@Failure	500		{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/transfer/{domain} [get]
+func (r *transferRoutes) exportDomain(c *gin.Context) {
+	q, err := bindTransferQuery(c)
+	if err != nil {
+		resp.Fail(c, http.StatusBadRequest, err)
+		return
+	}
+	export, err := r.transfer.Export(c, entity.TransferDomain(c.Param("domain")), entity.TransferFormat(q.Format))
+	if err != nil {
+		r.fail(c, err)
+		return
+	}
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": export.FileName}))
+	c.Header("Content-Type", export.ContentType)
+	c.Status(http.StatusOK)
+	_, _ = export.WriteTo(c.Writer)
+}
+
+// importDomain -.
+//
+//	@Tags		Transfer V1
+//	@Summary	Import the file of an export as body, fail policy writes nothing if any conflict, admin only
+//	@security	JWT
+//	@Accept		application/octet-stream
+//	@Produce	application/json
+//	@param		domain	path		string	true	"users, settings, stocks, futures or options"
+//	@param		format	query		string	false	"json (default), ndjson or protobuf"
+//	@param		policy	query		string	false	"skip, overwrite or fail (default)"
+//	@Success	200		{object}	entity.TransferResult
+//	@Failure	400		{object}	pb.APIResponse
+//	@Failure	403		{object}	pb.APIResponse
+//	@Failure	409		{object}	entity.TransferResult
+//	@Failure	500		{object}	pb.APIResponse
+//	@Router		/api/capitan/v1/system/transfer/{domain} [post]
+func (r *transferRoutes) importDomain(c *gin.Context) {
+	q, err := bindTransferQuery(c)
+	if err != nil {
+		resp.Fail(c, http.StatusBadRequest, err)
+		return
+	}
+	result, err := r.transfer.Import(
+		c, entity.TransferDomain(c.Param("domain")), entity.TransferFormat(q.Format),
+		c.Request.Body, entity.ConflictPolicy(q.Policy),
+	)
+	if err != nil {
+		if errors.Is(err, usecases.ErrTransferConflict) {
+			resp.Success(c, http.StatusConflict, result)
+			return
+		}
+		r.fail(c, err)
+		return
+	}
+	resp.Success(c, http.StatusOK, result)
+}
@@ -2,17 +2,33 @@ package ws
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// ErrShutdown is the cause of the request context cancelled on shutdown, the others are maintenance.
+var ErrShutdown = errors.New("shutdown")
+
 const (
 	pingMessage = "ping"
 	pongMessage = "pong"
+
+	closeTimeout = time.Second
 )
 
+var activeConns = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "capitan",
+	Subsystem: "ws",
+	Name:      "active_connections",
+	Help:      "Websockets open by route.",
+}, []string{"route"})
+
 type WS interface {
 	GetConn() *websocket.Conn
 	ReadMessage()
@@ -24,8 +40,9 @@ type ws struct {
 	binaryChan chan []byte
 	textChan   chan []byte
 
-	conn *websocket.Conn
-	ctx  context.Context
+	conn  *websocket.Conn
+	ctx   context.Context
+	route string
 
 	forwardChan chan []byte
 }
@@ -35,6 +52,7 @@ func New(c *gin.Context, forwardChan chan []byte) (WS, error) {
 		textChan:    make(chan []byte),
 		binaryChan:  make(chan []byte),
 		ctx:         c.Request.Context(),
+		route:       c.FullPath(),
 		forwardChan: forwardChan,
 	}
 	if err := w.upgrade(c); err != nil {
@@ -54,14 +72,24 @@ func (w *ws) upgrade(c *gin.Context) error {
 		return err
 	}
 	w.conn = conn
+	activeConns.WithLabelValues(w.route).Inc()
 	go w.writeMessage()
 	return nil
 }
 
+// writeMessage closes the connection when ctx done, e.g. drained for maintenance or shutdown,
+// or the handler returned after the read failed.
 func (w *ws) writeMessage() {
+	defer activeConns.WithLabelValues(w.route).Dec()
 	for {
 		select {
 		case <-w.ctx.Done():
+			msg := websocket.FormatCloseMessage(websocket.CloseServiceRestart, "maintenance")
+			if errors.Is(context.Cause(w.ctx), ErrShutdown) {
+				msg = websocket.FormatCloseMessage(websocket.CloseGoingAway, "shutdown")
+			}
+			_ = w.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(closeTimeout))
+			_ = w.conn.Close()
 			return
 		case cl := <-w.textChan:
 			_ = w.conn.WriteMessage(websocket.TextMessage, cl)
@@ -95,12 +123,18 @@ func (w *ws) WriteTextMessage(msg []byte) {
 	if msg == nil {
 		return
 	}
-	w.textChan <- msg
+	select {
+	case <-w.ctx.Done():
+	case w.textChan <- msg:
+	}
 }
 
 func (w *ws) WriteBinaryMessage(msg []byte) {
 	if msg == nil {
 		return
 	}
-	w.binaryChan <- msg
+	select {
+	case <-w.ctx.Done():
+	case w.binaryChan <- msg:
+	}
 }
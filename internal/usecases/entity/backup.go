@@ -0,0 +1,109 @@
+package entity
+
+import "time"
+
+// BackupSchedule is the automatic backup config, zero keep means no limit.
+type BackupSchedule struct {
+	Enabled   bool   `json:"enabled"`
+	Spec      string `json:"spec"`
+	KeepCount int    `json:"keep_count"`
+	KeepDays  int    `json:"keep_days"`
+}
+
+// BackupScheduleStatus is the config and the latest result of automatic backup,
+// NextRun is zero if disabled.
+type BackupScheduleStatus struct {
+	Schedule   *BackupSchedule `json:"schedule"`
+	Running    bool            `json:"running"`
+	NextRun    time.Time       `json:"next_run"`
+	LastRun    time.Time       `json:"last_run"`
+	LastBackup string          `json:"last_backup"`
+	LastError  string          `json:"last_error"`
+	Pruned     []string        `json:"pruned"`
+}
+
+// BackupFile is one file in the backup directory.
+type BackupFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// BackupManifest is written into the backup directory after dump,
+// SHA256 is the digest of all file digests sorted by name.
+// Encrypted backups list the sealed files, so the checksums are verified without the key.
+type BackupManifest struct {
+	Name           string           `json:"name"`
+	AppVersion     string           `json:"app_version"`
+	AppCommit      string           `json:"app_commit"`
+	Migration      int              `json:"migration"`
+	LocalMigration int              `json:"local_migration"`
+	Size           int64            `json:"size"`
+	SHA256         string           `json:"sha256"`
+	Files          []*BackupFile    `json:"files"`
+	RowCounts      map[string]int64 `json:"row_counts"`
+	Encrypted      bool             `json:"encrypted"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+// BackupVerifyReport is the result of the dry-run restore into a scratch database.
+// Row counts are taken right after dump, Mismatches may be writes during the dump.
+type BackupVerifyReport struct {
+	Files      bool             `json:"files"`
+	Schema     bool             `json:"schema"`
+	Restore    bool             `json:"restore"`
+	RowCounts  map[string]int64 `json:"row_counts"`
+	Mismatches []string         `json:"mismatches"`
+}
+
+type BackupTargetKind string
+
+const (
+	BackupTargetLocal BackupTargetKind = "local"
+	BackupTargetSFTP  BackupTargetKind = "sftp"
+	BackupTargetS3    BackupTargetKind = "s3"
+)
+
+// BackupTarget is an off-box storage the backups are replicated to, zero keep means no limit.
+// Path is the directory for local and sftp, the key prefix for s3.
+// Password, PrivateKey and SecretKey are never returned, empty on update keeps the saved one.
+type BackupTarget struct {
+	Name      string           `json:"name"`
+	Kind      BackupTargetKind `json:"kind"`
+	Enabled   bool             `json:"enabled"`
+	Path      string           `json:"path"`
+	KeepCount int              `json:"keep_count"`
+	KeepDays  int              `json:"keep_days"`
+
+	// sftp, Host is host:port, HostKey is the server public key in authorized_keys format.
+	Host       string `json:"host,omitempty"`
+	User       string `json:"user,omitempty"`
+	Password   string `json:"password,omitempty"`
+	PrivateKey string `json:"private_key,omitempty"`
+	HostKey    string `json:"host_key,omitempty"`
+
+	// s3
+	Endpoint  string `json:"endpoint,omitempty"`
+	Bucket    string `json:"bucket,omitempty"`
+	Region    string `json:"region,omitempty"`
+	AccessKey string `json:"access_key,omitempty"`
+	SecretKey string `json:"secret_key,omitempty"`
+	Insecure  bool   `json:"insecure,omitempty"`
+}
+
+// BackupTargetStatus is the target with secrets removed and the latest replication result.
+type BackupTargetStatus struct {
+	Target     *BackupTarget `json:"target"`
+	LastRun    time.Time     `json:"last_run"`
+	LastBackup string        `json:"last_backup"`
+	LastError  string        `json:"last_error"`
+	Pruned     []string      `json:"pruned"`
+}
+
+// RemoteBackup is a backup archive on a target.
+type RemoteBackup struct {
+	Target    string    `json:"target"`
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
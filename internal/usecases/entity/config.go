@@ -0,0 +1,7 @@
+package entity
+
+// ConfigReload is the keys changed since start, RestartRequired keeps the running values until the restart.
+type ConfigReload struct {
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restart_required"`
+}
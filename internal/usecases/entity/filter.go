@@ -0,0 +1,12 @@
+package entity
+
+// ContractFilter is the query filter of futures and options, empty field means no filter.
+type ContractFilter struct {
+	UnderlyingKind string `form:"underlying_kind"`
+	DeliveryMonth  string `form:"delivery_month"`
+	Category       string `form:"category"`
+	OptionRight    string `form:"option_right"`
+
+	// IncludeExpired also returns the active contracts after delivery date, internal use only.
+	IncludeExpired bool `form:"-"`
+}
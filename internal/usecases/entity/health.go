@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+// HealthStatus is up, degraded or down, only down is not ready.
+type HealthStatus string
+
+const (
+	HealthStatusUp       HealthStatus = "up"
+	HealthStatusDegraded HealthStatus = "degraded"
+	HealthStatusDown     HealthStatus = "down"
+)
+
+// Liveness is up as long as the process serves.
+type Liveness struct {
+	Status    HealthStatus `json:"status"`
+	StartedAt time.Time    `json:"started_at"`
+}
+
+// Readiness Status is the worst of the components.
+type Readiness struct {
+	Status     HealthStatus       `json:"status"`
+	CheckedAt  time.Time          `json:"checked_at"`
+	Components []*ComponentHealth `json:"components"`
+}
+
+// ComponentHealth Detail tells why not up, Metrics are the numbers checked.
+type ComponentHealth struct {
+	Name    string         `json:"name"`
+	Status  HealthStatus   `json:"status"`
+	Detail  string         `json:"detail,omitempty"`
+	Metrics map[string]any `json:"metrics,omitempty"`
+}
+
+// TickSubscription is a code of the tick streams, Default is subscribed by config, Active is streaming.
+type TickSubscription struct {
+	Code     string `json:"code"`
+	Default  bool   `json:"default"`
+	Active   bool   `json:"active"`
+	LastTick string `json:"last_tick"`
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// PriceHistory is the reference price of one code on one trading day, never updated once inserted.
+type PriceHistory struct {
+	Code       string         `json:"code"`
+	Kind       InstrumentKind `json:"kind"`
+	UpdateDate time.Time      `json:"update_date"`
+	Reference  float64        `json:"reference"`
+	LimitUp    float64        `json:"limit_up"`
+	LimitDown  float64        `json:"limit_down"`
+}
+
+// PriceSeries is the price history of one code of kind sorted by date ascending.
+type PriceSeries struct {
+	Code string          `json:"code"`
+	Kind InstrumentKind  `json:"kind"`
+	List []*PriceHistory `json:"list"`
+}
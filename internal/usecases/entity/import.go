@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+// ImportIssue is one row rejected by validation, it is not imported.
+type ImportIssue struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// StockRow is a validated row of basic_stock.
+type StockRow struct {
+	Code       string
+	Name       string
+	Exchange   string
+	Category   string
+	DayTrade   bool
+	Reference  float64
+	LimitUp    float64
+	LimitDown  float64
+	UpdateDate time.Time
+}
+
+// FutureRow is a validated row of basic_future.
+type FutureRow struct {
+	Code           string
+	Symbol         string
+	Name           string
+	Category       string
+	DeliveryMonth  string
+	DeliveryDate   time.Time
+	UnderlyingKind string
+	Unit           int64
+	LimitUp        float64
+	LimitDown      float64
+	Reference      float64
+	UpdateDate     time.Time
+}
+
+// OptionRow is a validated row of basic_option.
+type OptionRow struct {
+	FutureRow
+
+	StrikePrice float64
+	OptionRight string
+}
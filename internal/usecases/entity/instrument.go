@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+type InstrumentKind string
+
+const (
+	InstrumentKindStock  InstrumentKind = "stock"
+	InstrumentKindFuture InstrumentKind = "future"
+	InstrumentKindOption InstrumentKind = "option"
+)
+
+// Valid is true for the known kinds.
+func (k InstrumentKind) Valid() bool {
+	switch k {
+	case InstrumentKindStock, InstrumentKindFuture, InstrumentKindOption:
+		return true
+	}
+	return false
+}
+
+// InstrumentDiff is the difference between the latest refresh and the active rows before it.
+// Changed only counts the static attributes, not prices. Issues are the rows rejected by validation.
+type InstrumentDiff struct {
+	Kind        InstrumentKind `json:"kind"`
+	Added       []string       `json:"added"`
+	Removed     []string       `json:"removed"`
+	Changed     []string       `json:"changed"`
+	Issues      []*ImportIssue `json:"issues"`
+	RefreshedAt time.Time      `json:"refreshed_at"`
+}
+
+// IsEmpty is true if nothing added, removed or changed.
+func (d *InstrumentDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// RefreshSummary is the latest diff of each kind.
+type RefreshSummary struct {
+	Diffs []*InstrumentDiff `json:"diffs"`
+}
@@ -0,0 +1,68 @@
+package entity
+
+import "time"
+
+type JobKind string
+
+const (
+	JobKindBackup  JobKind = "backup"
+	JobKindRestore JobKind = "restore"
+	JobKindUpload  JobKind = "upload"
+	JobKindVerify  JobKind = "verify"
+)
+
+type JobState string
+
+const (
+	JobStateQueued  JobState = "queued"
+	JobStateRunning JobState = "running"
+	JobStateDone    JobState = "done"
+	JobStateFailed  JobState = "failed"
+)
+
+var jobTransitions = map[JobState][]JobState{
+	JobStateQueued:  {JobStateRunning, JobStateFailed},
+	JobStateRunning: {JobStateDone, JobStateFailed},
+}
+
+// CanTransit is true if the state machine allows from -> to, done and failed are final.
+func (s JobState) CanTransit(to JobState) bool {
+	for _, v := range jobTransitions[s] {
+		if v == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Finished is true if the state is final.
+func (s JobState) Finished() bool {
+	return s == JobStateDone || s == JobStateFailed
+}
+
+// Job is a background backup task, Target is the backup name or the uploaded file,
+// Artifact is the file produced by the job if any.
+type Job struct {
+	ID        string              `json:"id"`
+	Kind      JobKind             `json:"kind"`
+	State     JobState            `json:"state"`
+	Target    string              `json:"target"`
+	Progress  int                 `json:"progress"`
+	Message   string              `json:"message"`
+	Error     string              `json:"error"`
+	Artifact  string              `json:"artifact"`
+	Report    *BackupVerifyReport `json:"report,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// UploadSession is a resumable upload of a backup archive, Offset is the bytes received.
+// JobID is set once all bytes received and the archive is being loaded.
+type UploadSession struct {
+	ID        string    `json:"id"`
+	FileName  string    `json:"file_name"`
+	Size      int64     `json:"size"`
+	Offset    int64     `json:"offset"`
+	JobID     string    `json:"job_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,84 @@
+package entity
+
+const (
+	OptionRightCall string = "C"
+	OptionRightPut  string = "P"
+)
+
+// OptionQuote is one side of a strike in the option chain.
+type OptionQuote struct {
+	Code      string  `json:"code"`
+	Reference float64 `json:"reference"`
+	LimitUp   float64 `json:"limit_up"`
+	LimitDown float64 `json:"limit_down"`
+	LastPrice float64 `json:"last_price"`
+	LastTime  string  `json:"last_time"`
+}
+
+// OptionStrike is the call and put pair of the same strike price.
+type OptionStrike struct {
+	StrikePrice float64      `json:"strike_price"`
+	Call        *OptionQuote `json:"call"`
+	Put         *OptionQuote `json:"put"`
+}
+
+// OptionChain is the strike ladder of one underlying and delivery month, strikes sorted ascending.
+type OptionChain struct {
+	Underlying    string          `json:"underlying"`
+	DeliveryMonth string          `json:"delivery_month"`
+	Strikes       []*OptionStrike `json:"strikes"`
+}
+
+// Codes returns all contract codes in the chain.
+func (c *OptionChain) Codes() []string {
+	codes := []string{}
+	for _, s := range c.Strikes {
+		if s.Call != nil {
+			codes = append(codes, s.Call.Code)
+		}
+		if s.Put != nil {
+			codes = append(codes, s.Put.Code)
+		}
+	}
+	return codes
+}
+
+// MergeLastPrice sets the last price of code, returns false if code is not in the chain or price not changed.
+func (c *OptionChain) MergeLastPrice(code string, price float64, dateTime string) bool {
+	for _, s := range c.Strikes {
+		for _, q := range []*OptionQuote{s.Call, s.Put} {
+			if q == nil || q.Code != code {
+				continue
+			}
+			if q.LastPrice == price && q.LastTime == dateTime {
+				return false
+			}
+			q.LastPrice = price
+			q.LastTime = dateTime
+			return true
+		}
+	}
+	return false
+}
+
+// Clone returns a deep copy of the chain.
+func (c *OptionChain) Clone() *OptionChain {
+	result := &OptionChain{
+		Underlying:    c.Underlying,
+		DeliveryMonth: c.DeliveryMonth,
+		Strikes:       make([]*OptionStrike, 0, len(c.Strikes)),
+	}
+	for _, s := range c.Strikes {
+		strike := &OptionStrike{StrikePrice: s.StrikePrice}
+		if s.Call != nil {
+			call := *s.Call
+			strike.Call = &call
+		}
+		if s.Put != nil {
+			put := *s.Put
+			strike.Put = &put
+		}
+		result.Strikes = append(result.Strikes, strike)
+	}
+	return result
+}
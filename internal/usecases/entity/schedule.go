@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// RefreshStatus is the status of the scheduled instrument master data refresh.
+type RefreshStatus struct {
+	Running     bool      `json:"running"`
+	LastSuccess time.Time `json:"last_success"`
+	LastAttempt time.Time `json:"last_attempt"`
+	LastError   string    `json:"last_error"`
+	NextRun     time.Time `json:"next_run"`
+}
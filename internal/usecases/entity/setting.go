@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// LocalSettingKey is a key of capitan_setting, owned by capitan apart from the pb.SettingKey of panther.
+type LocalSettingKey string
+
+// Setting is a known key of system_setting or capitan_setting, the secret fields of Value are always empty.
+// Key is the pb.SettingKey, zero for capitan_setting. Stored is false if Value is the default.
+type Setting struct {
+	Name     string `json:"name"`
+	Key      int32  `json:"key,omitempty"`
+	ReadOnly bool   `json:"read_only"`
+	Stored   bool   `json:"stored"`
+	Value    any    `json:"value"`
+}
+
+// JWTSetting is the signing secret of the tokens, generated on first start.
+type JWTSetting struct {
+	Secret    string    `json:"secret"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
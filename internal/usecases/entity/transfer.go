@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// TransferDomain is the data exported and imported as a whole.
+type TransferDomain string
+
+const (
+	TransferDomainUsers    TransferDomain = "users"
+	TransferDomainSettings TransferDomain = "settings"
+	TransferDomainStocks   TransferDomain = "stocks"
+	TransferDomainFutures  TransferDomain = "futures"
+	TransferDomainOptions  TransferDomain = "options"
+)
+
+// TransferFormat is the encoding of an export file.
+type TransferFormat string
+
+const (
+	// TransferFormatJSON is one indented document of the header and the records.
+	TransferFormatJSON TransferFormat = "json"
+	// TransferFormatNDJSON is the header on the first line, then one record per line.
+	TransferFormatNDJSON TransferFormat = "ndjson"
+	// TransferFormatProtobuf is size delimited messages, the header first.
+	TransferFormatProtobuf TransferFormat = "protobuf"
+)
+
+// ConflictPolicy decides what an import does to the records already existing.
+type ConflictPolicy string
+
+const (
+	ConflictPolicySkip      ConflictPolicy = "skip"
+	ConflictPolicyOverwrite ConflictPolicy = "overwrite"
+	ConflictPolicyFail      ConflictPolicy = "fail"
+)
+
+// TransferHeader leads every export file, Version is the layout of the file, not the app.
+type TransferHeader struct {
+	Magic          string         `json:"magic"`
+	Version        int            `json:"version"`
+	Domain         TransferDomain `json:"domain"`
+	AppVersion     string         `json:"app_version"`
+	Migration      int            `json:"migration"`
+	LocalMigration int            `json:"local_migration"`
+	Count          int            `json:"count"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// SettingRecord is one row of capitan_setting, Value is set if the content is json, Raw otherwise.
+type SettingRecord struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value,omitempty"`
+	Raw   []byte          `json:"raw,omitempty"`
+}
+
+// TransferResult is the outcome of an import, Conflicts are the existing records found.
+// Skipped counts the records not written, the unchanged, the conflicts kept and the Issues.
+type TransferResult struct {
+	Domain    TransferDomain `json:"domain"`
+	Total     int            `json:"total"`
+	Inserted  int            `json:"inserted"`
+	Updated   int            `json:"updated"`
+	Skipped   int            `json:"skipped"`
+	Conflicts []string       `json:"conflicts"`
+	Issues    []*ImportIssue `json:"issues"`
+}
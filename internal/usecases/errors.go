@@ -19,4 +19,35 @@ var (
 	ErrRoleInvalid           = &UseCaseError{Code: -1007, Message: "role invalid"}
 	ErrMfaCodeRequired       = &UseCaseError{Code: -1008, Message: "mfa code required"}
 	ErrMfaCodeNotMatch       = &UseCaseError{Code: -1009, Message: "mfa code not match"}
+
+	ErrRefreshInProgress = &UseCaseError{Code: -2001, Message: "refresh in progress"}
+
+	ErrBackupScheduleInvalid = &UseCaseError{Code: -3001, Message: "backup schedule invalid"}
+	ErrRestoreInProgress     = &UseCaseError{Code: -3002, Message: "restore in progress"}
+	ErrBackupNotFound        = &UseCaseError{Code: -3003, Message: "backup not found"}
+	ErrJobNotFound           = &UseCaseError{Code: -3004, Message: "job not found"}
+	ErrBackupCorrupted       = &UseCaseError{Code: -3005, Message: "backup corrupted"}
+	ErrBackupSchemaMismatch  = &UseCaseError{Code: -3006, Message: "backup schema newer than current"}
+	ErrBackupManifestMissing = &UseCaseError{Code: -3007, Message: "backup manifest missing"}
+	ErrBackupKeyRequired     = &UseCaseError{Code: -3008, Message: "backup encryption key or passphrase required"}
+	ErrBackupDecrypt         = &UseCaseError{Code: -3009, Message: "backup decrypt failed"}
+	ErrBackupTargetInvalid   = &UseCaseError{Code: -3010, Message: "backup target invalid"}
+	ErrBackupTargetNotFound  = &UseCaseError{Code: -3011, Message: "backup target not found"}
+	ErrUploadNotFound        = &UseCaseError{Code: -3012, Message: "upload not found"}
+	ErrUploadOffsetMismatch  = &UseCaseError{Code: -3013, Message: "upload offset mismatch"}
+	ErrUploadTooLarge        = &UseCaseError{Code: -3014, Message: "upload too large"}
+
+	ErrTransferDomainInvalid = &UseCaseError{Code: -4001, Message: "transfer domain invalid"}
+	ErrTransferFormatInvalid = &UseCaseError{Code: -4002, Message: "transfer format invalid"}
+	ErrConflictPolicyInvalid = &UseCaseError{Code: -4003, Message: "conflict policy invalid"}
+	ErrTransferFileInvalid   = &UseCaseError{Code: -4004, Message: "transfer file invalid"}
+	ErrTransferConflict      = &UseCaseError{Code: -4005, Message: "transfer conflict with existing records"}
+
+	ErrSettingNotFound = &UseCaseError{Code: -5001, Message: "setting not found"}
+	ErrSettingInvalid  = &UseCaseError{Code: -5002, Message: "setting invalid"}
+	ErrSettingReadOnly = &UseCaseError{Code: -5003, Message: "setting read only"}
+
+	ErrConfigInvalid = &UseCaseError{Code: -6001, Message: "config invalid"}
+
+	ErrUpstreamUnavailable = &UseCaseError{Code: -7001, Message: "upstream unavailable"}
 )
@@ -0,0 +1,20 @@
+package client
+
+import (
+	"context"
+)
+
+const authorizationKey = "authorization"
+
+// bearerAuth is the authorization metadata of every rpc, refused by grpc on a connection without TLS.
+type bearerAuth string
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (a bearerAuth) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{authorizationKey: "Bearer " + string(a)}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials.
+func (a bearerAuth) RequireTransportSecurity() bool {
+	return true
+}
@@ -2,14 +2,46 @@
 package client
 
 import (
+	"errors"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/stats"
 )
 
-func NewInsecureClient(gRPCPath string) (*grpc.ClientConn, error) {
-	conn, err := grpc.NewClient(gRPCPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return nil, err
+// NewClient is insecure without TLS, the files of TLS and ClientCert are read again on each handshake if changed.
+// BearerToken requires TLS, never sent in plaintext.
+// Every rpc has a request id, logged at debug and measured by method and code on /metrics, also traced.
+func NewClient(gRPCPath string, opts ...Option) (*grpc.ClientConn, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.token != "" && !o.tls {
+		return nil, errors.New("bearer token requires TLS")
+	}
+	creds := insecure.NewCredentials()
+	if o.tls {
+		cfg, err := newTLSConfig(o)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(cfg)
+	}
+	i := newInterceptor(o)
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(otelgrpc.WithFilter(func(info *stats.RPCTagInfo) bool {
+			_, untraced := o.untraced[info.FullMethodName]
+			return !untraced
+		}))),
+		grpc.WithChainUnaryInterceptor(i.unaryChain()...),
+		grpc.WithChainStreamInterceptor(i.streamChain()...),
+	}
+	if o.token != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(bearerAuth(o.token)))
 	}
-	return conn, nil
+	return grpc.NewClient(gRPCPath, dialOpts...)
 }
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/chindada/leopard/pkg/log"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// RequestIDKey is the metadata of the request id, the same one is kept over the retries.
+	RequestIDKey = "x-request-id"
+
+	retryBackoff    = 200 * time.Millisecond
+	retryBackoffMax = 3 * time.Second
+)
+
+// interceptor is the chain of NewClient, in order of request id, deadline, retry then observe.
+type interceptor struct {
+	logger *log.Log
+	opts   *options
+}
+
+func newInterceptor(opts *options) *interceptor {
+	return &interceptor{
+		logger: log.Get(),
+		opts:   opts,
+	}
+}
+
+func (i *interceptor) unaryChain() []grpc.UnaryClientInterceptor {
+	return []grpc.UnaryClientInterceptor{i.requestIDUnary, i.deadline, i.retry, i.observeUnary}
+}
+
+// streamChain has no deadline and retry, the streams are long lived and not idempotent.
+func (i *interceptor) streamChain() []grpc.StreamClientInterceptor {
+	return []grpc.StreamClientInterceptor{i.requestIDStream, i.observeStream}
+}
+
+// withRequestID keeps the request id of ctx, or generates one.
+func withRequestID(ctx context.Context) (context.Context, string) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if ids := md.Get(RequestIDKey); len(ids) > 0 && ids[0] != "" {
+		return ctx, ids[0]
+	}
+	id := uuid.NewString()
+	return WithRequestID(ctx, id), id
+}
+
+// WithRequestID sends id as the request id of the rpcs made with ctx, e.g. the id of the http request.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, RequestIDKey, id)
+}
+
+func requestID(ctx context.Context) string {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if ids := md.Get(RequestIDKey); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
+
+func (i *interceptor) requestIDUnary(
+	ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+) error {
+	ctx, _ = withRequestID(ctx)
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+func (i *interceptor) requestIDStream(
+	ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	ctx, _ = withRequestID(ctx)
+	return streamer(ctx, desc, cc, method, opts...)
+}
+
+// deadline sets the timeout of the method if ctx has no deadline, the deadline given by the caller wins.
+func (i *interceptor) deadline(
+	ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+) error {
+	timeout, ok := i.opts.methodTimeouts[method]
+	if !ok {
+		timeout = i.opts.timeout
+	}
+	if _, has := ctx.Deadline(); !has && timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// retry retries the idempotent methods if unavailable, with exponential backoff bounded by the deadline.
+func (i *interceptor) retry(
+	ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+) error {
+	times := 0
+	if _, ok := i.opts.retryMethods[method]; ok {
+		times = i.opts.retryTimes
+	}
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil || attempt >= times || status.Code(err) != codes.Unavailable {
+			return err
+		}
+		i.logger.Debugf("gRPC %s [%s] retry %d/%d after %s: %v", method, requestID(ctx), attempt+1, times, backoff, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, retryBackoffMax)
+	}
+}
+
+// observeUnary logs and measures each attempt.
+func (i *interceptor) observeUnary(
+	ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	i.observe(ctx, method, start, err)
+	return err
+}
+
+// observeStream logs and measures the stream opening only, not the whole stream.
+func (i *interceptor) observeStream(
+	ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	start := time.Now()
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	i.observe(ctx, method, start, err)
+	return stream, err
+}
+
+func (i *interceptor) observe(ctx context.Context, method string, start time.Time, err error) {
+	elapsed := time.Since(start)
+	code := status.Code(err)
+	handlingSeconds.WithLabelValues(method, code.String()).Observe(elapsed.Seconds())
+	i.logger.Debugf("gRPC %s [%s] %s in %s", method, requestID(ctx), code, elapsed.Round(time.Microsecond))
+}
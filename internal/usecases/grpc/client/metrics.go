@@ -0,0 +1,15 @@
+package client
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// handlingSeconds is on the default registry, served by /metrics.
+var handlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "capitan",
+	Subsystem: "grpc_client",
+	Name:      "handling_seconds",
+	Help:      "Latency of the panther rpc by method and status code, each retry and stream opening counted.",
+	Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+}, []string{"method", "code"})
@@ -0,0 +1,87 @@
+package client
+
+import "time"
+
+// Option of NewClient.
+type Option func(*options)
+
+type options struct {
+	tls        bool
+	caFile     string
+	serverName string
+	certFile   string
+	keyFile    string
+	token      string
+
+	timeout        time.Duration
+	methodTimeouts map[string]time.Duration
+	retryTimes     int
+	retryMethods   map[string]struct{}
+	untraced       map[string]struct{}
+}
+
+// TLS verifies the server by caFile, or the system roots if empty. serverName overrides the host of target.
+func TLS(caFile, serverName string) Option {
+	return func(o *options) {
+		o.tls = true
+		o.caFile = caFile
+		o.serverName = serverName
+	}
+}
+
+// ClientCert presents the cert for mutual tls, only used with TLS.
+func ClientCert(certFile, keyFile string) Option {
+	return func(o *options) {
+		o.certFile = certFile
+		o.keyFile = keyFile
+	}
+}
+
+// BearerToken is sent as authorization metadata of every rpc, requires TLS.
+func BearerToken(token string) Option {
+	return func(o *options) {
+		o.token = token
+	}
+}
+
+// Timeout is the deadline of the unary rpc if the context has none, zero is no deadline.
+func Timeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// MethodTimeout overrides Timeout of the full method, e.g. /basic.BasicInterface/GetAllStockDetail.
+func MethodTimeout(method string, timeout time.Duration) Option {
+	return func(o *options) {
+		if o.methodTimeouts == nil {
+			o.methodTimeouts = map[string]time.Duration{}
+		}
+		o.methodTimeouts[method] = timeout
+	}
+}
+
+// Retry retries the unary methods up to times if unavailable, only for the idempotent ones.
+func Retry(times int, methods ...string) Option {
+	return func(o *options) {
+		o.retryTimes = times
+		if o.retryMethods == nil {
+			o.retryMethods = map[string]struct{}{}
+		}
+		for _, m := range methods {
+			o.retryMethods[m] = struct{}{}
+		}
+	}
+}
+
+// Untraced skips the tracing of the methods, e.g. the health channel open all the time.
+func Untraced(methods ...string) Option {
+	return func(o *options) {
+		if o.untraced == nil {
+			o.untraced = map[string]struct{}{}
+		}
+		for _, m := range methods {
+			o.untraced[m] = struct{}{}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileCache keeps the value parsed from files, parsed again if any mod time changed.
+type fileCache[T any] struct {
+	lock     sync.Mutex
+	files    []string
+	modTimes []time.Time
+	value    T
+	parse    func() (T, error)
+}
+
+func newFileCache[T any](parse func() (T, error), files ...string) *fileCache[T] {
+	return &fileCache[T]{files: files, parse: parse}
+}
+
+// get returns the last good value if the files are being rotated, missing or not parsable.
+func (c *fileCache[T]) get() (T, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	modTimes := make([]time.Time, len(c.files))
+	changed := c.modTimes == nil
+	for i, f := range c.files {
+		info, err := os.Stat(f)
+		if err != nil && c.modTimes != nil {
+			return c.value, nil
+		}
+		if err != nil {
+			return c.value, err
+		}
+		modTimes[i] = info.ModTime()
+		if !changed && !modTimes[i].Equal(c.modTimes[i]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return c.value, nil
+	}
+	v, err := c.parse()
+	if err != nil {
+		if c.modTimes != nil {
+			return c.value, nil
+		}
+		return v, err
+	}
+	c.value = v
+	c.modTimes = modTimes
+	return v, nil
+}
+
+func newTLSConfig(o *options) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: o.serverName,
+	}
+	if (o.certFile == "") != (o.keyFile == "") {
+		return nil, errors.New("client cert and key must be set together")
+	}
+	if o.certFile != "" {
+		certs := newFileCache(func() (*tls.Certificate, error) {
+			cert, err := tls.LoadX509KeyPair(o.certFile, o.keyFile)
+			return &cert, err
+		}, o.certFile, o.keyFile)
+		if _, err := certs.get(); err != nil {
+			return nil, fmt.Errorf("client cert: %w", err)
+		}
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return certs.get()
+		}
+	}
+	if o.caFile == "" {
+		return cfg, nil
+	}
+	roots := newFileCache(func() (*x509.CertPool, error) {
+		pem, err := os.ReadFile(o.caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate in %s", o.caFile)
+		}
+		return pool, nil
+	}, o.caFile)
+	if _, err := roots.get(); err != nil {
+		return nil, fmt.Errorf("ca: %w", err)
+	}
+	// the roots may rotate, so verified here instead of RootCAs
+	cfg.InsecureSkipVerify = true
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		pool, err := roots.get()
+		if err != nil {
+			return err
+		}
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("no server certificate")
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range cs.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err = cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+			DNSName:       cs.ServerName,
+			Roots:         pool,
+			Intermediates: intermediates,
+		})
+		return err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+var (
+	ticksReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "capitan",
+		Subsystem: "stream",
+		Name:      "ticks_total",
+		Help:      "Ticks received from panther by code.",
+	}, []string{"code"})
+	tickLag = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "capitan",
+		Subsystem: "stream",
+		Name:      "tick_lag_seconds",
+		Help:      "Time from the tick to received by code.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 30},
+	}, []string{"code"})
+	logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "capitan",
+		Name:      "logins_total",
+		Help:      "Logins by response code, OK is success.",
+	}, []string{"code"})
+	backupDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "capitan",
+		Subsystem: "backup",
+		Name:      "duration_seconds",
+		Help:      "Time to create a backup by result.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"result"})
+	backupSize = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "capitan",
+		Subsystem: "backup",
+		Name:      "last_size_bytes",
+		Help:      "Size of the last backup created.",
+	})
+	refreshDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "capitan",
+		Subsystem: "instrument_refresh",
+		Name:      "duration_seconds",
+		Help:      "Time of an instrument refresh by result.",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 10),
+	}, []string{"result"})
+	refreshRows = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "capitan",
+		Subsystem: "instrument_refresh",
+		Name:      "rows",
+		Help:      "Rows of the last refresh by kind and state, imported or rejected.",
+	}, []string{"kind", "state"})
+)
+
+func result(err error) string {
+	if err != nil {
+		return resultFailure
+	}
+	return resultSuccess
+}
@@ -0,0 +1,372 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase_backup.go
+//
+// Generated by this command:
+//
+//	mockgen -source=usecase_backup.go -destination=./mocks/mocks_usecase_backup_test.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	usecases "github.com/chindada/capitan/internal/usecases"
+	entity "github.com/chindada/capitan/internal/usecases/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMaintainer is a mock of Maintainer interface.
+type MockMaintainer struct {
+	ctrl     *gomock.Controller
+	recorder *MockMaintainerMockRecorder
+	isgomock struct{}
+}
+
+// MockMaintainerMockRecorder is the mock recorder for MockMaintainer.
+type MockMaintainerMockRecorder struct {
+	mock *MockMaintainer
+}
+
+// NewMockMaintainer creates a new mock instance.
+func NewMockMaintainer(ctrl *gomock.Controller) *MockMaintainer {
+	mock := &MockMaintainer{ctrl: ctrl}
+	mock.recorder = &MockMaintainerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMaintainer) EXPECT() *MockMaintainerMockRecorder {
+	return m.recorder
+}
+
+// Drain mocks base method.
+func (m *MockMaintainer) Drain(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drain", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Drain indicates an expected call of Drain.
+func (mr *MockMaintainerMockRecorder) Drain(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drain", reflect.TypeOf((*MockMaintainer)(nil).Drain), ctx)
+}
+
+// Resume mocks base method.
+func (m *MockMaintainer) Resume(rebind func()) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resume", rebind)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resume indicates an expected call of Resume.
+func (mr *MockMaintainerMockRecorder) Resume(rebind any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resume", reflect.TypeOf((*MockMaintainer)(nil).Resume), rebind)
+}
+
+// MockBackup is a mock of Backup interface.
+type MockBackup struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackupMockRecorder
+	isgomock struct{}
+}
+
+// MockBackupMockRecorder is the mock recorder for MockBackup.
+type MockBackupMockRecorder struct {
+	mock *MockBackup
+}
+
+// NewMockBackup creates a new mock instance.
+func NewMockBackup(ctrl *gomock.Controller) *MockBackup {
+	mock := &MockBackup{ctrl: ctrl}
+	mock.recorder = &MockBackupMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackup) EXPECT() *MockBackupMockRecorder {
+	return m.recorder
+}
+
+// AppendUploadSession mocks base method.
+func (m *MockBackup) AppendUploadSession(id string, offset int64, chunk io.Reader, passphrase string) (*entity.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendUploadSession", id, offset, chunk, passphrase)
+	ret0, _ := ret[0].(*entity.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AppendUploadSession indicates an expected call of AppendUploadSession.
+func (mr *MockBackupMockRecorder) AppendUploadSession(id, offset, chunk, passphrase any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendUploadSession", reflect.TypeOf((*MockBackup)(nil).AppendUploadSession), id, offset, chunk, passphrase)
+}
+
+// Close mocks base method.
+func (m *MockBackup) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockBackupMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockBackup)(nil).Close))
+}
+
+// CreateBackupJob mocks base method.
+func (m *MockBackup) CreateBackupJob() (*entity.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBackupJob")
+	ret0, _ := ret[0].(*entity.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBackupJob indicates an expected call of CreateBackupJob.
+func (mr *MockBackupMockRecorder) CreateBackupJob() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBackupJob", reflect.TypeOf((*MockBackup)(nil).CreateBackupJob))
+}
+
+// CreateRemoteRestoreJob mocks base method.
+func (m *MockBackup) CreateRemoteRestoreJob(target, name string) (*entity.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRemoteRestoreJob", target, name)
+	ret0, _ := ret[0].(*entity.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRemoteRestoreJob indicates an expected call of CreateRemoteRestoreJob.
+func (mr *MockBackupMockRecorder) CreateRemoteRestoreJob(target, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRemoteRestoreJob", reflect.TypeOf((*MockBackup)(nil).CreateRemoteRestoreJob), target, name)
+}
+
+// CreateRestoreJob mocks base method.
+func (m *MockBackup) CreateRestoreJob(name string) (*entity.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRestoreJob", name)
+	ret0, _ := ret[0].(*entity.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRestoreJob indicates an expected call of CreateRestoreJob.
+func (mr *MockBackupMockRecorder) CreateRestoreJob(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRestoreJob", reflect.TypeOf((*MockBackup)(nil).CreateRestoreJob), name)
+}
+
+// CreateUploadSession mocks base method.
+func (m *MockBackup) CreateUploadSession(fileName string, size int64) (*entity.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUploadSession", fileName, size)
+	ret0, _ := ret[0].(*entity.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUploadSession indicates an expected call of CreateUploadSession.
+func (mr *MockBackupMockRecorder) CreateUploadSession(fileName, size any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUploadSession", reflect.TypeOf((*MockBackup)(nil).CreateUploadSession), fileName, size)
+}
+
+// CreateVerifyJob mocks base method.
+func (m *MockBackup) CreateVerifyJob(name string) (*entity.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVerifyJob", name)
+	ret0, _ := ret[0].(*entity.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVerifyJob indicates an expected call of CreateVerifyJob.
+func (mr *MockBackupMockRecorder) CreateVerifyJob(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVerifyJob", reflect.TypeOf((*MockBackup)(nil).CreateVerifyJob), name)
+}
+
+// DeleteUploadSession mocks base method.
+func (m *MockBackup) DeleteUploadSession(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUploadSession", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUploadSession indicates an expected call of DeleteUploadSession.
+func (mr *MockBackupMockRecorder) DeleteUploadSession(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUploadSession", reflect.TypeOf((*MockBackup)(nil).DeleteUploadSession), id)
+}
+
+// GetJob mocks base method.
+func (m *MockBackup) GetJob(id string) (*entity.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJob", id)
+	ret0, _ := ret[0].(*entity.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJob indicates an expected call of GetJob.
+func (mr *MockBackupMockRecorder) GetJob(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockBackup)(nil).GetJob), id)
+}
+
+// GetScheduleStatus mocks base method.
+func (m *MockBackup) GetScheduleStatus() *entity.BackupScheduleStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduleStatus")
+	ret0, _ := ret[0].(*entity.BackupScheduleStatus)
+	return ret0
+}
+
+// GetScheduleStatus indicates an expected call of GetScheduleStatus.
+func (mr *MockBackupMockRecorder) GetScheduleStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduleStatus", reflect.TypeOf((*MockBackup)(nil).GetScheduleStatus))
+}
+
+// GetTargets mocks base method.
+func (m *MockBackup) GetTargets() []*entity.BackupTargetStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTargets")
+	ret0, _ := ret[0].([]*entity.BackupTargetStatus)
+	return ret0
+}
+
+// GetTargets indicates an expected call of GetTargets.
+func (mr *MockBackupMockRecorder) GetTargets() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTargets", reflect.TypeOf((*MockBackup)(nil).GetTargets))
+}
+
+// GetUploadSession mocks base method.
+func (m *MockBackup) GetUploadSession(id string) (*entity.UploadSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUploadSession", id)
+	ret0, _ := ret[0].(*entity.UploadSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUploadSession indicates an expected call of GetUploadSession.
+func (mr *MockBackupMockRecorder) GetUploadSession(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUploadSession", reflect.TypeOf((*MockBackup)(nil).GetUploadSession), id)
+}
+
+// ListJobs mocks base method.
+func (m *MockBackup) ListJobs() []*entity.Job {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJobs")
+	ret0, _ := ret[0].([]*entity.Job)
+	return ret0
+}
+
+// ListJobs indicates an expected call of ListJobs.
+func (mr *MockBackupMockRecorder) ListJobs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobs", reflect.TypeOf((*MockBackup)(nil).ListJobs))
+}
+
+// ListRemoteBackups mocks base method.
+func (m *MockBackup) ListRemoteBackups(ctx context.Context, target string) ([]*entity.RemoteBackup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRemoteBackups", ctx, target)
+	ret0, _ := ret[0].([]*entity.RemoteBackup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRemoteBackups indicates an expected call of ListRemoteBackups.
+func (mr *MockBackupMockRecorder) ListRemoteBackups(ctx, target any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRemoteBackups", reflect.TypeOf((*MockBackup)(nil).ListRemoteBackups), ctx, target)
+}
+
+// OpenArchive mocks base method.
+func (m *MockBackup) OpenArchive(name, passphrase string) (*usecases.BackupArchive, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenArchive", name, passphrase)
+	ret0, _ := ret[0].(*usecases.BackupArchive)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenArchive indicates an expected call of OpenArchive.
+func (mr *MockBackupMockRecorder) OpenArchive(name, passphrase any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenArchive", reflect.TypeOf((*MockBackup)(nil).OpenArchive), name, passphrase)
+}
+
+// SubscribeJob mocks base method.
+func (m *MockBackup) SubscribeJob(ctx context.Context, id string) (<-chan *entity.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeJob", ctx, id)
+	ret0, _ := ret[0].(<-chan *entity.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeJob indicates an expected call of SubscribeJob.
+func (mr *MockBackupMockRecorder) SubscribeJob(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeJob", reflect.TypeOf((*MockBackup)(nil).SubscribeJob), ctx, id)
+}
+
+// UpdateSchedule mocks base method.
+func (m *MockBackup) UpdateSchedule(ctx context.Context, s *entity.BackupSchedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSchedule", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSchedule indicates an expected call of UpdateSchedule.
+func (mr *MockBackupMockRecorder) UpdateSchedule(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSchedule", reflect.TypeOf((*MockBackup)(nil).UpdateSchedule), ctx, s)
+}
+
+// UpdateTargets mocks base method.
+func (m *MockBackup) UpdateTargets(ctx context.Context, targets []*entity.BackupTarget) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTargets", ctx, targets)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTargets indicates an expected call of UpdateTargets.
+func (mr *MockBackupMockRecorder) UpdateTargets(ctx, targets any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTargets", reflect.TypeOf((*MockBackup)(nil).UpdateTargets), ctx, targets)
+}
+
+// UploadBackup mocks base method.
+func (m *MockBackup) UploadBackup(fileName string, src io.Reader, passphrase string) (*entity.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadBackup", fileName, src, passphrase)
+	ret0, _ := ret[0].(*entity.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadBackup indicates an expected call of UploadBackup.
+func (mr *MockBackupMockRecorder) UploadBackup(fileName, src, passphrase any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadBackup", reflect.TypeOf((*MockBackup)(nil).UploadBackup), fileName, src, passphrase)
+}
@@ -12,7 +12,9 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	entity "github.com/chindada/capitan/internal/usecases/entity"
 	pb "github.com/chindada/panther/golang/pb"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,6 +43,18 @@ func (m *MockBasic) EXPECT() *MockBasicMockRecorder {
 	return m.recorder
 }
 
+// Close mocks base method.
+func (m *MockBasic) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockBasicMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockBasic)(nil).Close))
+}
+
 // GetAllStockDetail mocks base method.
 func (m *MockBasic) GetAllStockDetail(ctx context.Context) (*pb.StockDetailList, error) {
 	m.ctrl.T.Helper()
@@ -55,3 +69,362 @@ func (mr *MockBasicMockRecorder) GetAllStockDetail(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllStockDetail", reflect.TypeOf((*MockBasic)(nil).GetAllStockDetail), ctx)
 }
+
+// GetFutureDetail mocks base method.
+func (m *MockBasic) GetFutureDetail(ctx context.Context, filter *entity.ContractFilter) (*pb.FutureDetailList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFutureDetail", ctx, filter)
+	ret0, _ := ret[0].(*pb.FutureDetailList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFutureDetail indicates an expected call of GetFutureDetail.
+func (mr *MockBasicMockRecorder) GetFutureDetail(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFutureDetail", reflect.TypeOf((*MockBasic)(nil).GetFutureDetail), ctx, filter)
+}
+
+// GetOptionChain mocks base method.
+func (m *MockBasic) GetOptionChain(ctx context.Context, underlying, month string) (*entity.OptionChain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOptionChain", ctx, underlying, month)
+	ret0, _ := ret[0].(*entity.OptionChain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOptionChain indicates an expected call of GetOptionChain.
+func (mr *MockBasicMockRecorder) GetOptionChain(ctx, underlying, month any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOptionChain", reflect.TypeOf((*MockBasic)(nil).GetOptionChain), ctx, underlying, month)
+}
+
+// GetOptionDetail mocks base method.
+func (m *MockBasic) GetOptionDetail(ctx context.Context, filter *entity.ContractFilter) (*pb.OptionDetailList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOptionDetail", ctx, filter)
+	ret0, _ := ret[0].(*pb.OptionDetailList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOptionDetail indicates an expected call of GetOptionDetail.
+func (mr *MockBasicMockRecorder) GetOptionDetail(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOptionDetail", reflect.TypeOf((*MockBasic)(nil).GetOptionDetail), ctx, filter)
+}
+
+// GetPriceSeries mocks base method.
+func (m *MockBasic) GetPriceSeries(ctx context.Context, kind entity.InstrumentKind, code string, from, to time.Time) (*entity.PriceSeries, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceSeries", ctx, kind, code, from, to)
+	ret0, _ := ret[0].(*entity.PriceSeries)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceSeries indicates an expected call of GetPriceSeries.
+func (mr *MockBasicMockRecorder) GetPriceSeries(ctx, kind, code, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceSeries", reflect.TypeOf((*MockBasic)(nil).GetPriceSeries), ctx, kind, code, from, to)
+}
+
+// GetRefreshStatus mocks base method.
+func (m *MockBasic) GetRefreshStatus() *entity.RefreshStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshStatus")
+	ret0, _ := ret[0].(*entity.RefreshStatus)
+	return ret0
+}
+
+// GetRefreshStatus indicates an expected call of GetRefreshStatus.
+func (mr *MockBasicMockRecorder) GetRefreshStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshStatus", reflect.TypeOf((*MockBasic)(nil).GetRefreshStatus))
+}
+
+// GetRefreshSummary mocks base method.
+func (m *MockBasic) GetRefreshSummary() *entity.RefreshSummary {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshSummary")
+	ret0, _ := ret[0].(*entity.RefreshSummary)
+	return ret0
+}
+
+// GetRefreshSummary indicates an expected call of GetRefreshSummary.
+func (mr *MockBasicMockRecorder) GetRefreshSummary() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshSummary", reflect.TypeOf((*MockBasic)(nil).GetRefreshSummary))
+}
+
+// Start mocks base method.
+func (m *MockBasic) Start() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Start")
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockBasicMockRecorder) Start() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockBasic)(nil).Start))
+}
+
+// SubscribeOptionChain mocks base method.
+func (m *MockBasic) SubscribeOptionChain(ctx context.Context, underlying, month string) (<-chan *entity.OptionChain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeOptionChain", ctx, underlying, month)
+	ret0, _ := ret[0].(<-chan *entity.OptionChain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeOptionChain indicates an expected call of SubscribeOptionChain.
+func (mr *MockBasicMockRecorder) SubscribeOptionChain(ctx, underlying, month any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeOptionChain", reflect.TypeOf((*MockBasic)(nil).SubscribeOptionChain), ctx, underlying, month)
+}
+
+// TriggerRefresh mocks base method.
+func (m *MockBasic) TriggerRefresh() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TriggerRefresh")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TriggerRefresh indicates an expected call of TriggerRefresh.
+func (mr *MockBasicMockRecorder) TriggerRefresh() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TriggerRefresh", reflect.TypeOf((*MockBasic)(nil).TriggerRefresh))
+}
+
+// MockcodeDetail is a mock of codeDetail interface.
+type MockcodeDetail struct {
+	ctrl     *gomock.Controller
+	recorder *MockcodeDetailMockRecorder
+	isgomock struct{}
+}
+
+// MockcodeDetailMockRecorder is the mock recorder for MockcodeDetail.
+type MockcodeDetailMockRecorder struct {
+	mock *MockcodeDetail
+}
+
+// NewMockcodeDetail creates a new mock instance.
+func NewMockcodeDetail(ctrl *gomock.Controller) *MockcodeDetail {
+	mock := &MockcodeDetail{ctrl: ctrl}
+	mock.recorder = &MockcodeDetailMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockcodeDetail) EXPECT() *MockcodeDetailMockRecorder {
+	return m.recorder
+}
+
+// GetCode mocks base method.
+func (m *MockcodeDetail) GetCode() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCode")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetCode indicates an expected call of GetCode.
+func (mr *MockcodeDetailMockRecorder) GetCode() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCode", reflect.TypeOf((*MockcodeDetail)(nil).GetCode))
+}
+
+// MockcontractDetail is a mock of contractDetail interface.
+type MockcontractDetail struct {
+	ctrl     *gomock.Controller
+	recorder *MockcontractDetailMockRecorder
+	isgomock struct{}
+}
+
+// MockcontractDetailMockRecorder is the mock recorder for MockcontractDetail.
+type MockcontractDetailMockRecorder struct {
+	mock *MockcontractDetail
+}
+
+// NewMockcontractDetail creates a new mock instance.
+func NewMockcontractDetail(ctrl *gomock.Controller) *MockcontractDetail {
+	mock := &MockcontractDetail{ctrl: ctrl}
+	mock.recorder = &MockcontractDetailMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockcontractDetail) EXPECT() *MockcontractDetailMockRecorder {
+	return m.recorder
+}
+
+// GetCategory mocks base method.
+func (m *MockcontractDetail) GetCategory() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategory")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetCategory indicates an expected call of GetCategory.
+func (mr *MockcontractDetailMockRecorder) GetCategory() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*MockcontractDetail)(nil).GetCategory))
+}
+
+// GetCode mocks base method.
+func (m *MockcontractDetail) GetCode() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCode")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetCode indicates an expected call of GetCode.
+func (mr *MockcontractDetailMockRecorder) GetCode() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCode", reflect.TypeOf((*MockcontractDetail)(nil).GetCode))
+}
+
+// GetDeliveryDate mocks base method.
+func (m *MockcontractDetail) GetDeliveryDate() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveryDate")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetDeliveryDate indicates an expected call of GetDeliveryDate.
+func (mr *MockcontractDetailMockRecorder) GetDeliveryDate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryDate", reflect.TypeOf((*MockcontractDetail)(nil).GetDeliveryDate))
+}
+
+// GetDeliveryMonth mocks base method.
+func (m *MockcontractDetail) GetDeliveryMonth() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveryMonth")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetDeliveryMonth indicates an expected call of GetDeliveryMonth.
+func (mr *MockcontractDetailMockRecorder) GetDeliveryMonth() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryMonth", reflect.TypeOf((*MockcontractDetail)(nil).GetDeliveryMonth))
+}
+
+// GetLimitDown mocks base method.
+func (m *MockcontractDetail) GetLimitDown() float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimitDown")
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// GetLimitDown indicates an expected call of GetLimitDown.
+func (mr *MockcontractDetailMockRecorder) GetLimitDown() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimitDown", reflect.TypeOf((*MockcontractDetail)(nil).GetLimitDown))
+}
+
+// GetLimitUp mocks base method.
+func (m *MockcontractDetail) GetLimitUp() float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLimitUp")
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// GetLimitUp indicates an expected call of GetLimitUp.
+func (mr *MockcontractDetailMockRecorder) GetLimitUp() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLimitUp", reflect.TypeOf((*MockcontractDetail)(nil).GetLimitUp))
+}
+
+// GetName mocks base method.
+func (m *MockcontractDetail) GetName() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetName")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetName indicates an expected call of GetName.
+func (mr *MockcontractDetailMockRecorder) GetName() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetName", reflect.TypeOf((*MockcontractDetail)(nil).GetName))
+}
+
+// GetReference mocks base method.
+func (m *MockcontractDetail) GetReference() float64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReference")
+	ret0, _ := ret[0].(float64)
+	return ret0
+}
+
+// GetReference indicates an expected call of GetReference.
+func (mr *MockcontractDetailMockRecorder) GetReference() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReference", reflect.TypeOf((*MockcontractDetail)(nil).GetReference))
+}
+
+// GetSymbol mocks base method.
+func (m *MockcontractDetail) GetSymbol() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSymbol")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetSymbol indicates an expected call of GetSymbol.
+func (mr *MockcontractDetailMockRecorder) GetSymbol() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSymbol", reflect.TypeOf((*MockcontractDetail)(nil).GetSymbol))
+}
+
+// GetUnderlyingKind mocks base method.
+func (m *MockcontractDetail) GetUnderlyingKind() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnderlyingKind")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetUnderlyingKind indicates an expected call of GetUnderlyingKind.
+func (mr *MockcontractDetailMockRecorder) GetUnderlyingKind() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnderlyingKind", reflect.TypeOf((*MockcontractDetail)(nil).GetUnderlyingKind))
+}
+
+// GetUnit mocks base method.
+func (m *MockcontractDetail) GetUnit() int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnit")
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// GetUnit indicates an expected call of GetUnit.
+func (mr *MockcontractDetailMockRecorder) GetUnit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnit", reflect.TypeOf((*MockcontractDetail)(nil).GetUnit))
+}
+
+// GetUpdateDate mocks base method.
+func (m *MockcontractDetail) GetUpdateDate() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpdateDate")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetUpdateDate indicates an expected call of GetUpdateDate.
+func (mr *MockcontractDetailMockRecorder) GetUpdateDate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpdateDate", reflect.TypeOf((*MockcontractDetail)(nil).GetUpdateDate))
+}
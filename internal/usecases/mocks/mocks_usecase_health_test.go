@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase_health.go
+//
+// Generated by this command:
+//
+//	mockgen -source=usecase_health.go -destination=./mocks/mocks_usecase_health_test.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/chindada/capitan/internal/usecases/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRuntime is a mock of Runtime interface.
+type MockRuntime struct {
+	ctrl     *gomock.Controller
+	recorder *MockRuntimeMockRecorder
+	isgomock struct{}
+}
+
+// MockRuntimeMockRecorder is the mock recorder for MockRuntime.
+type MockRuntimeMockRecorder struct {
+	mock *MockRuntime
+}
+
+// NewMockRuntime creates a new mock instance.
+func NewMockRuntime(ctrl *gomock.Controller) *MockRuntime {
+	mock := &MockRuntime{ctrl: ctrl}
+	mock.recorder = &MockRuntimeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRuntime) EXPECT() *MockRuntimeMockRecorder {
+	return m.recorder
+}
+
+// GetRefreshStatus mocks base method.
+func (m *MockRuntime) GetRefreshStatus() *entity.RefreshStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshStatus")
+	ret0, _ := ret[0].(*entity.RefreshStatus)
+	return ret0
+}
+
+// GetRefreshStatus indicates an expected call of GetRefreshStatus.
+func (mr *MockRuntimeMockRecorder) GetRefreshStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshStatus", reflect.TypeOf((*MockRuntime)(nil).GetRefreshStatus))
+}
+
+// InMaintenance mocks base method.
+func (m *MockRuntime) InMaintenance() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InMaintenance")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// InMaintenance indicates an expected call of InMaintenance.
+func (mr *MockRuntimeMockRecorder) InMaintenance() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InMaintenance", reflect.TypeOf((*MockRuntime)(nil).InMaintenance))
+}
+
+// MockHealth is a mock of Health interface.
+type MockHealth struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthMockRecorder
+	isgomock struct{}
+}
+
+// MockHealthMockRecorder is the mock recorder for MockHealth.
+type MockHealthMockRecorder struct {
+	mock *MockHealth
+}
+
+// NewMockHealth creates a new mock instance.
+func NewMockHealth(ctrl *gomock.Controller) *MockHealth {
+	mock := &MockHealth{ctrl: ctrl}
+	mock.recorder = &MockHealthMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealth) EXPECT() *MockHealthMockRecorder {
+	return m.recorder
+}
+
+// Live mocks base method.
+func (m *MockHealth) Live() *entity.Liveness {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Live")
+	ret0, _ := ret[0].(*entity.Liveness)
+	return ret0
+}
+
+// Live indicates an expected call of Live.
+func (mr *MockHealthMockRecorder) Live() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Live", reflect.TypeOf((*MockHealth)(nil).Live))
+}
+
+// Ready mocks base method.
+func (m *MockHealth) Ready(ctx context.Context) *entity.Readiness {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ready", ctx)
+	ret0, _ := ret[0].(*entity.Readiness)
+	return ret0
+}
+
+// Ready indicates an expected call of Ready.
+func (mr *MockHealthMockRecorder) Ready(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ready", reflect.TypeOf((*MockHealth)(nil).Ready), ctx)
+}
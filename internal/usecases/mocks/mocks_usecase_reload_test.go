@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase_reload.go
+//
+// Generated by this command:
+//
+//	mockgen -source=usecase_reload.go -destination=./mocks/mocks_usecase_reload_test.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/chindada/capitan/internal/usecases/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReloader is a mock of Reloader interface.
+type MockReloader struct {
+	ctrl     *gomock.Controller
+	recorder *MockReloaderMockRecorder
+	isgomock struct{}
+}
+
+// MockReloaderMockRecorder is the mock recorder for MockReloader.
+type MockReloaderMockRecorder struct {
+	mock *MockReloader
+}
+
+// NewMockReloader creates a new mock instance.
+func NewMockReloader(ctrl *gomock.Controller) *MockReloader {
+	mock := &MockReloader{ctrl: ctrl}
+	mock.recorder = &MockReloaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReloader) EXPECT() *MockReloaderMockRecorder {
+	return m.recorder
+}
+
+// ReloadConfig mocks base method.
+func (m *MockReloader) ReloadConfig(ctx context.Context) (*entity.ConfigReload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReloadConfig", ctx)
+	ret0, _ := ret[0].(*entity.ConfigReload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReloadConfig indicates an expected call of ReloadConfig.
+func (mr *MockReloaderMockRecorder) ReloadConfig(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReloadConfig", reflect.TypeOf((*MockReloader)(nil).ReloadConfig), ctx)
+}
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase_setting.go
+//
+// Generated by this command:
+//
+//	mockgen -source=usecase_setting.go -destination=./mocks/mocks_usecase_setting_test.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	json "encoding/json"
+	reflect "reflect"
+
+	entity "github.com/chindada/capitan/internal/usecases/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSettings is a mock of Settings interface.
+type MockSettings struct {
+	ctrl     *gomock.Controller
+	recorder *MockSettingsMockRecorder
+	isgomock struct{}
+}
+
+// MockSettingsMockRecorder is the mock recorder for MockSettings.
+type MockSettingsMockRecorder struct {
+	mock *MockSettings
+}
+
+// NewMockSettings creates a new mock instance.
+func NewMockSettings(ctrl *gomock.Controller) *MockSettings {
+	mock := &MockSettings{ctrl: ctrl}
+	mock.recorder = &MockSettingsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSettings) EXPECT() *MockSettingsMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockSettings) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockSettingsMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSettings)(nil).Close))
+}
+
+// GetSetting mocks base method.
+func (m *MockSettings) GetSetting(ctx context.Context, name string) (*entity.Setting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSetting", ctx, name)
+	ret0, _ := ret[0].(*entity.Setting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSetting indicates an expected call of GetSetting.
+func (mr *MockSettingsMockRecorder) GetSetting(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSetting", reflect.TypeOf((*MockSettings)(nil).GetSetting), ctx, name)
+}
+
+// ListSettings mocks base method.
+func (m *MockSettings) ListSettings(ctx context.Context) ([]*entity.Setting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSettings", ctx)
+	ret0, _ := ret[0].([]*entity.Setting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSettings indicates an expected call of ListSettings.
+func (mr *MockSettingsMockRecorder) ListSettings(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSettings", reflect.TypeOf((*MockSettings)(nil).ListSettings), ctx)
+}
+
+// UpdateSetting mocks base method.
+func (m *MockSettings) UpdateSetting(ctx context.Context, name string, value json.RawMessage) (*entity.Setting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSetting", ctx, name, value)
+	ret0, _ := ret[0].(*entity.Setting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSetting indicates an expected call of UpdateSetting.
+func (mr *MockSettingsMockRecorder) UpdateSetting(ctx, name, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSetting", reflect.TypeOf((*MockSettings)(nil).UpdateSetting), ctx, name, value)
+}
@@ -10,6 +10,10 @@
 package mocks
 
 import (
+	reflect "reflect"
+
+	entity "github.com/chindada/capitan/internal/usecases/entity"
+	pb "github.com/chindada/panther/golang/pb"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -36,3 +40,58 @@ func NewMockStream(ctrl *gomock.Controller) *MockStream {
 func (m *MockStream) EXPECT() *MockStreamMockRecorder {
 	return m.recorder
 }
+
+// Close mocks base method.
+func (m *MockStream) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockStreamMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockStream)(nil).Close))
+}
+
+// GetLastTick mocks base method.
+func (m *MockStream) GetLastTick(code string) *pb.FutureTick {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastTick", code)
+	ret0, _ := ret[0].(*pb.FutureTick)
+	return ret0
+}
+
+// GetLastTick indicates an expected call of GetLastTick.
+func (mr *MockStreamMockRecorder) GetLastTick(code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastTick", reflect.TypeOf((*MockStream)(nil).GetLastTick), code)
+}
+
+// GetSubscriptions mocks base method.
+func (m *MockStream) GetSubscriptions() []*entity.TickSubscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptions")
+	ret0, _ := ret[0].([]*entity.TickSubscription)
+	return ret0
+}
+
+// GetSubscriptions indicates an expected call of GetSubscriptions.
+func (mr *MockStreamMockRecorder) GetSubscriptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptions", reflect.TypeOf((*MockStream)(nil).GetSubscriptions))
+}
+
+// SubscribeTick mocks base method.
+func (m *MockStream) SubscribeTick(codes []string) (<-chan *pb.FutureTick, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeTick", codes)
+	ret0, _ := ret[0].(<-chan *pb.FutureTick)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// SubscribeTick indicates an expected call of SubscribeTick.
+func (mr *MockStreamMockRecorder) SubscribeTick(codes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeTick", reflect.TypeOf((*MockStream)(nil).SubscribeTick), codes)
+}
@@ -0,0 +1,74 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: usecase_transfer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=usecase_transfer.go -destination=./mocks/mocks_usecase_transfer_test.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	usecases "github.com/chindada/capitan/internal/usecases"
+	entity "github.com/chindada/capitan/internal/usecases/entity"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransfer is a mock of Transfer interface.
+type MockTransfer struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferMockRecorder
+	isgomock struct{}
+}
+
+// MockTransferMockRecorder is the mock recorder for MockTransfer.
+type MockTransferMockRecorder struct {
+	mock *MockTransfer
+}
+
+// NewMockTransfer creates a new mock instance.
+func NewMockTransfer(ctrl *gomock.Controller) *MockTransfer {
+	mock := &MockTransfer{ctrl: ctrl}
+	mock.recorder = &MockTransferMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransfer) EXPECT() *MockTransferMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockTransfer) Export(ctx context.Context, domain entity.TransferDomain, format entity.TransferFormat) (*usecases.TransferExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, domain, format)
+	ret0, _ := ret[0].(*usecases.TransferExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockTransferMockRecorder) Export(ctx, domain, format any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockTransfer)(nil).Export), ctx, domain, format)
+}
+
+// Import mocks base method.
+func (m *MockTransfer) Import(ctx context.Context, domain entity.TransferDomain, format entity.TransferFormat, src io.Reader, policy entity.ConflictPolicy) (*entity.TransferResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, domain, format, src, policy)
+	ret0, _ := ret[0].(*entity.TransferResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockTransferMockRecorder) Import(ctx, domain, format, src, policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockTransfer)(nil).Import), ctx, domain, format, src, policy)
+}
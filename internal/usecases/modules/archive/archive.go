@@ -0,0 +1,236 @@
+// Package archive streams a backup directory as zip without a temp file.
+//
+// The zip is stored, not deflated, with the file sizes and times taken once, so the same
+// directory always gives the same bytes. The size is known before streaming and a range is
+// served by generating again and skipping to the offset.
+package archive
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// dataDescriptorFlag puts the crc after the data, so the file is read once.
+const dataDescriptorFlag = 0x8
+
+var ErrChanged = errors.New("backup changed while streaming")
+
+type file struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+// Archive is the zip of the files in dir, entries are named base/file as the launcher zips.
+type Archive struct {
+	dir   string
+	files []file
+	size  int64
+}
+
+// New takes the file list of dir, the files must not change until streamed.
+func New(dir string) (*Archive, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	a := &Archive{dir: dir}
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		info, iErr := e.Info()
+		if iErr != nil {
+			return nil, iErr
+		}
+		a.files = append(a.files, file{
+			name:    e.Name(),
+			size:    info.Size(),
+			modTime: info.ModTime().UTC().Truncate(time.Second),
+		})
+	}
+	sort.Slice(a.files, func(i, j int) bool {
+		return a.files[i].name < a.files[j].name
+	})
+	counter := &countWriter{}
+	if err = a.write(counter, func(file) (io.ReadCloser, error) {
+		return io.NopCloser(zeroReader{}), nil
+	}); err != nil {
+		return nil, err
+	}
+	a.size = counter.n
+	return a, nil
+}
+
+// Size is the length of the zip.
+func (a *Archive) Size() int64 {
+	return a.size
+}
+
+// ModTime is the latest modified time of the files.
+func (a *Archive) ModTime() time.Time {
+	var latest time.Time
+	for _, f := range a.files {
+		if f.modTime.After(latest) {
+			latest = f.modTime
+		}
+	}
+	return latest
+}
+
+// ETag changes if any file is renamed, resized or touched.
+func (a *Archive) ETag() string {
+	h := crc32.NewIEEE()
+	for _, f := range a.files {
+		_, _ = fmt.Fprintf(h, "%s %d %d\n", f.name, f.size, f.modTime.Unix())
+	}
+	return fmt.Sprintf(`"%08x-%x"`, h.Sum32(), a.size)
+}
+
+// WriteTo writes the whole zip to w.
+func (a *Archive) WriteTo(w io.Writer) (int64, error) {
+	counter := &countWriter{w: w}
+	err := a.write(counter, a.open)
+	return counter.n, err
+}
+
+// NewReader returns the zip as a ReadSeeker for http.ServeContent.
+func (a *Archive) NewReader() io.ReadSeekCloser {
+	return &reader{archive: a}
+}
+
+func (a *Archive) open(f file) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(a.dir, f.name))
+}
+
+func (a *Archive) write(w io.Writer, open func(file) (io.ReadCloser, error)) error {
+	zw := zip.NewWriter(w)
+	base := filepath.Base(a.dir)
+	for _, f := range a.files {
+		fh := &zip.FileHeader{
+			Name:               path.Join(base, f.name),
+			Method:             zip.Store,
+			Flags:              dataDescriptorFlag,
+			Modified:           f.modTime,
+			CompressedSize64:   uint64(f.size),
+			UncompressedSize64: uint64(f.size),
+		}
+		fh.SetMode(0o600)
+		entry, err := zw.CreateRaw(fh)
+		if err != nil {
+			return err
+		}
+		src, err := open(f)
+		if err != nil {
+			return err
+		}
+		crc := crc32.NewIEEE()
+		n, err := io.Copy(io.MultiWriter(entry, crc), io.LimitReader(src, f.size))
+		_ = src.Close()
+		if err != nil {
+			return err
+		}
+		if n != f.size {
+			return fmt.Errorf("%w: %s", ErrChanged, f.name)
+		}
+		fh.CRC32 = crc.Sum32()
+	}
+	return zw.Close()
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	if c.w == nil {
+		c.n += int64(len(p))
+		return len(p), nil
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// reader generates the zip in a goroutine, seeking backward restarts it, both skip to the offset.
+type reader struct {
+	archive *Archive
+	offset  int64
+	pipe    *io.PipeReader
+	pos     int64
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if r.offset >= r.archive.size {
+		return 0, io.EOF
+	}
+	if r.pipe == nil || r.pos > r.offset {
+		r.restart()
+		r.pos = 0
+	}
+	if r.pos < r.offset {
+		if _, err := io.CopyN(io.Discard, r.pipe, r.offset-r.pos); err != nil {
+			return 0, err
+		}
+		r.pos = r.offset
+	}
+	n, err := r.pipe.Read(p)
+	r.pos += int64(n)
+	r.offset = r.pos
+	return n, err
+}
+
+func (r *reader) restart() {
+	r.stop()
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := r.archive.WriteTo(pw)
+		_ = pw.CloseWithError(err)
+	}()
+	r.pipe = pr
+}
+
+func (r *reader) stop() {
+	if r.pipe != nil {
+		_ = r.pipe.Close()
+		r.pipe = nil
+	}
+}
+
+func (r *reader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.archive.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative offset")
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+func (r *reader) Close() error {
+	r.stop()
+	return nil
+}
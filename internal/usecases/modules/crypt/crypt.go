@@ -0,0 +1,239 @@
+// Package crypt encrypts backup files with AES-256-GCM in 64 KiB chunks,
+// the key is derived from a passphrase by scrypt with a random salt per file.
+//
+// Layout: magic | salt | nonce prefix | chunks, each chunk is sealed with
+// nonce prefix + counter and the final flag as additional data, so truncation is detected.
+package crypt
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Ext is appended to the name of an encrypted file.
+const Ext = ".enc"
+
+const (
+	chunkSize  = 64 * 1024
+	saltSize   = 16
+	prefixSize = 8
+	keySize    = 32
+	scryptN    = 1 << 15
+	scryptR    = 8
+	scryptP    = 1
+	flagMore   = 0
+	flagLast   = 1
+)
+
+var magic = []byte("CAPITAN-ENC-V1\n")
+
+var (
+	ErrDecrypt            = errors.New("decrypt failed, wrong passphrase or corrupted")
+	ErrPassphraseRequired = errors.New("passphrase required")
+)
+
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	if passphrase == "" {
+		return nil, ErrPassphraseRequired
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func nonce(prefix []byte, counter uint32) []byte {
+	n := make([]byte, prefixSize+4)
+	copy(n, prefix)
+	binary.BigEndian.PutUint32(n[prefixSize:], counter)
+	return n
+}
+
+// Encrypt reads src until EOF and writes the sealed stream to dst.
+func Encrypt(dst io.Writer, src io.Reader, passphrase string) error {
+	header := make([]byte, saltSize+prefixSize)
+	if _, err := rand.Read(header); err != nil {
+		return err
+	}
+	aead, err := newAEAD(passphrase, header[:saltSize])
+	if err != nil {
+		return err
+	}
+	prefix := header[saltSize:]
+	if _, err = dst.Write(append(append([]byte{}, magic...), header...)); err != nil {
+		return err
+	}
+	r := bufio.NewReaderSize(src, chunkSize)
+	buf := make([]byte, chunkSize)
+	for counter := uint32(0); ; counter++ {
+		n, rErr := io.ReadFull(r, buf)
+		if rErr != nil && !errors.Is(rErr, io.ErrUnexpectedEOF) && !errors.Is(rErr, io.EOF) {
+			return rErr
+		}
+		flag := byte(flagMore)
+		if rErr != nil {
+			flag = flagLast
+		} else if _, pErr := r.Peek(1); errors.Is(pErr, io.EOF) {
+			flag = flagLast
+		}
+		sealed := aead.Seal(nil, nonce(prefix, counter), buf[:n], []byte{flag})
+		if _, err = dst.Write(sealed); err != nil {
+			return err
+		}
+		if flag == flagLast {
+			return nil
+		}
+	}
+}
+
+// Decrypt returns ErrDecrypt if the passphrase is wrong or the stream is modified or truncated.
+func Decrypt(dst io.Writer, src io.Reader, passphrase string) error {
+	r := bufio.NewReaderSize(src, chunkSize+aes.BlockSize)
+	head := make([]byte, len(magic)+saltSize+prefixSize)
+	if _, err := io.ReadFull(r, head); err != nil || !bytes.Equal(head[:len(magic)], magic) {
+		return ErrDecrypt
+	}
+	aead, err := newAEAD(passphrase, head[len(magic):len(magic)+saltSize])
+	if err != nil {
+		return err
+	}
+	prefix := head[len(magic)+saltSize:]
+	buf := make([]byte, chunkSize+aead.Overhead())
+	for counter := uint32(0); ; counter++ {
+		n, rErr := io.ReadFull(r, buf)
+		if rErr != nil && !errors.Is(rErr, io.ErrUnexpectedEOF) {
+			return ErrDecrypt
+		}
+		flag := byte(flagMore)
+		if rErr != nil {
+			flag = flagLast
+		} else if _, pErr := r.Peek(1); errors.Is(pErr, io.EOF) {
+			flag = flagLast
+		}
+		plain, oErr := aead.Open(nil, nonce(prefix, counter), buf[:n], []byte{flag})
+		if oErr != nil {
+			return ErrDecrypt
+		}
+		if _, err = dst.Write(plain); err != nil {
+			return err
+		}
+		if flag == flagLast {
+			return nil
+		}
+	}
+}
+
+// IsEncrypted checks the magic at the head of the file.
+func IsEncrypted(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	head := make([]byte, len(magic))
+	if _, err = io.ReadFull(f, head); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return false, nil
+		}
+		return false, err
+	}
+	return bytes.Equal(head, magic), nil
+}
+
+// EncryptFile writes src to dst sealed, src is kept.
+func EncryptFile(src, dst, passphrase string) error {
+	return transform(src, dst, passphrase, Encrypt)
+}
+
+// DecryptFile writes src to dst opened, dst is removed if failed.
+func DecryptFile(src, dst, passphrase string) error {
+	return transform(src, dst, passphrase, Decrypt)
+}
+
+func transform(src, dst, passphrase string, fn func(io.Writer, io.Reader, string) error) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if err = fn(out, in, passphrase); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return err
+	}
+	if err = out.Close(); err != nil {
+		_ = os.Remove(dst)
+		return err
+	}
+	return nil
+}
+
+// EncryptDir seals every file in dir except skip to the name with Ext, the plaintext is removed.
+func EncryptDir(dir, passphrase string, skip ...string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || slices.Contains(skip, e.Name()) || filepath.Ext(e.Name()) == Ext {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		if err = EncryptFile(path, path+Ext, passphrase); err != nil {
+			return err
+		}
+		if err = os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecryptDir opens every sealed file in src into dst without Ext, dst can be src.
+// It returns the opened files, nothing is left in dst if failed.
+func DecryptDir(src, dst, passphrase string) ([]string, error) {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return nil, err
+	}
+	opened := []string{}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != Ext {
+			continue
+		}
+		path := filepath.Join(dst, strings.TrimSuffix(e.Name(), Ext))
+		if err = DecryptFile(filepath.Join(src, e.Name()), path, passphrase); err != nil {
+			for _, v := range opened {
+				_ = os.Remove(v)
+			}
+			return nil, err
+		}
+		opened = append(opened, path)
+	}
+	return opened, nil
+}
@@ -0,0 +1,123 @@
+// Package manifest writes and checks the manifest in a backup directory.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/chindada/capitan/internal/usecases/entity"
+)
+
+// FileName is the manifest in the backup directory, it is not listed in itself.
+const FileName = "manifest.json"
+
+var (
+	ErrNotFound  = errors.New("manifest not found")
+	ErrCorrupted = errors.New("backup corrupted")
+)
+
+// Hash returns the files in dir sorted by name, the total size and the digest of all file digests.
+func Hash(dir string) ([]*entity.BackupFile, int64, string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	files := []*entity.BackupFile{}
+	var size int64
+	for _, e := range entries {
+		if e.IsDir() || e.Name() == FileName {
+			continue
+		}
+		f, hErr := hashFile(filepath.Join(dir, e.Name()))
+		if hErr != nil {
+			return nil, 0, "", hErr
+		}
+		files = append(files, f)
+		size += f.Size
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+	return files, size, digest(files), nil
+}
+
+func hashFile(path string) (*entity.BackupFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.BackupFile{
+		Name:   filepath.Base(path),
+		Size:   size,
+		SHA256: hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+func digest(files []*entity.BackupFile) string {
+	h := sha256.New()
+	for _, f := range files {
+		_, _ = fmt.Fprintf(h, "%s %s\n", f.SHA256, f.Name)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func Write(dir string, m *entity.BackupManifest) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, FileName), content, 0o600)
+}
+
+// Read returns ErrNotFound if the backup is created before manifest introduced.
+func Read(dir string) (*entity.BackupManifest, error) {
+	content, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	m := entity.BackupManifest{}
+	if err = json.Unmarshal(content, &m); err != nil {
+		return nil, fmt.Errorf("%w: invalid manifest: %w", ErrCorrupted, err)
+	}
+	return &m, nil
+}
+
+// Verify checks every file listed in m, extra files in dir are ignored.
+func Verify(dir string, m *entity.BackupManifest) error {
+	problems := []error{}
+	for _, want := range m.Files {
+		got, err := hashFile(filepath.Join(dir, filepath.Base(want.Name)))
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", want.Name, err))
+			continue
+		}
+		if got.Size != want.Size || got.SHA256 != want.SHA256 {
+			problems = append(problems, fmt.Errorf("%s: checksum mismatch", want.Name))
+		}
+	}
+	if digest(m.Files) != m.SHA256 {
+		problems = append(problems, errors.New("manifest digest mismatch"))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %w", ErrCorrupted, errors.Join(problems...))
+	}
+	return nil
+}
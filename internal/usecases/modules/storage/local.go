@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/chindada/capitan/internal/usecases/entity"
+)
+
+// local is a directory on the host, usually a NFS or SMB mount.
+type local struct {
+	dir string
+}
+
+func newLocal(t *entity.BackupTarget) (*local, error) {
+	if err := os.MkdirAll(t.Path, 0o750); err != nil {
+		return nil, err
+	}
+	return &local{dir: t.Path}, nil
+}
+
+func (l *local) Put(_ context.Context, name string, src io.Reader, _ int64) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(l.dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err = io.Copy(tmp, src); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(l.dir, name))
+}
+
+func (l *local) Get(_ context.Context, name string) (io.ReadCloser, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filepath.Join(l.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (l *local) List(_ context.Context) ([]Object, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, err
+	}
+	result := []Object{}
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		info, iErr := e.Info()
+		if iErr != nil {
+			return nil, iErr
+		}
+		result = append(result, Object{Name: e.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+	return result, nil
+}
+
+func (l *local) Delete(_ context.Context, name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	err := os.Remove(filepath.Join(l.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (l *local) Close() error {
+	return nil
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chindada/capitan/internal/usecases/entity"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3Target works with AWS S3 and the compatible stores like MinIO, objects are under the prefix.
+type s3Target struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func newS3(ctx context.Context, t *entity.BackupTarget) (*s3Target, error) {
+	client, err := minio.New(t.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(t.AccessKey, t.SecretKey, ""),
+		Secure: !t.Insecure,
+		Region: t.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+	exists, err := client.BucketExists(ctx, t.Bucket)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("bucket %s not found", t.Bucket)
+	}
+	prefix := strings.Trim(t.Path, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &s3Target{client: client, bucket: t.Bucket, prefix: prefix}, nil
+}
+
+// Put is atomic by S3, the object is visible only after uploaded.
+func (s *s3Target) Put(ctx context.Context, name string, src io.Reader, size int64) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	_, err := s.client.PutObject(ctx, s.bucket, s.prefix+name, src, size, minio.PutObjectOptions{
+		ContentType: "application/octet-stream",
+	})
+	return err
+}
+
+func (s *s3Target) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+	obj, err := s.client.GetObject(ctx, s.bucket, s.prefix+name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = obj.Stat(); err != nil {
+		_ = obj.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (s *s3Target) List(ctx context.Context) ([]Object, error) {
+	result := []Object{}
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: s.prefix}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		name := strings.TrimPrefix(obj.Key, s.prefix)
+		if name == "" || strings.Contains(name, "/") {
+			continue
+		}
+		result = append(result, Object{Name: name, Size: obj.Size, ModTime: obj.LastModified})
+	}
+	return result, nil
+}
+
+func (s *s3Target) Delete(ctx context.Context, name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	return s.client.RemoveObject(ctx, s.bucket, s.prefix+name, minio.RemoveObjectOptions{})
+}
+
+func (s *s3Target) Close() error {
+	return nil
+}
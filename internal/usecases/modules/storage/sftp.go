@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+
+	"github.com/chindada/capitan/internal/usecases/entity"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// sftpTarget keeps one ssh connection until closed, the host key is always checked.
+type sftpTarget struct {
+	conn   *ssh.Client
+	client *sftp.Client
+	dir    string
+}
+
+func newSFTP(ctx context.Context, t *entity.BackupTarget) (*sftpTarget, error) {
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(t.HostKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid host key: %w", err)
+	}
+	auth := []ssh.AuthMethod{}
+	if t.PrivateKey != "" {
+		signer, pErr := ssh.ParsePrivateKey([]byte(t.PrivateKey))
+		if pErr != nil {
+			return nil, fmt.Errorf("invalid private key: %w", pErr)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if t.Password != "" {
+		auth = append(auth, ssh.Password(t.Password))
+	}
+	addr := t.Host
+	if _, _, sErr := net.SplitHostPort(addr); sErr != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+	var d net.Dialer
+	raw, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(raw, addr, &ssh.ClientConfig{
+		User:            t.User,
+		Auth:            auth,
+		HostKeyCallback: ssh.FixedHostKey(hostKey),
+	})
+	if err != nil {
+		_ = raw.Close()
+		return nil, err
+	}
+	conn := ssh.NewClient(c, chans, reqs)
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if err = client.MkdirAll(t.Path); err != nil {
+		_ = client.Close()
+		_ = conn.Close()
+		return nil, err
+	}
+	return &sftpTarget{conn: conn, client: client, dir: t.Path}, nil
+}
+
+func (s *sftpTarget) Put(_ context.Context, name string, src io.Reader, _ int64) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	tmp := path.Join(s.dir, name+".tmp")
+	f, err := s.client.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err = f.ReadFrom(src); err != nil {
+		_ = f.Close()
+		_ = s.client.Remove(tmp)
+		return err
+	}
+	if err = f.Close(); err != nil {
+		_ = s.client.Remove(tmp)
+		return err
+	}
+	if err = s.client.PosixRename(tmp, path.Join(s.dir, name)); err != nil {
+		_ = s.client.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+func (s *sftpTarget) Get(_ context.Context, name string) (io.ReadCloser, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+	f, err := s.client.Open(path.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s *sftpTarget) List(_ context.Context) ([]Object, error) {
+	entries, err := s.client.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	result := []Object{}
+	for _, e := range entries {
+		if !e.Mode().IsRegular() {
+			continue
+		}
+		result = append(result, Object{Name: e.Name(), Size: e.Size(), ModTime: e.ModTime()})
+	}
+	return result, nil
+}
+
+func (s *sftpTarget) Delete(_ context.Context, name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	err := s.client.Remove(path.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (s *sftpTarget) Close() error {
+	return errors.Join(s.client.Close(), s.conn.Close())
+}
@@ -0,0 +1,89 @@
+// Package storage puts the backup archives on the off-box targets.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/chindada/capitan/internal/usecases/entity"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// Object is a file on the target, Name has no directory or prefix.
+type Object struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Target stores flat named objects under the path of the target.
+// Put must not leave a partial object under name if failed.
+type Target interface {
+	Put(ctx context.Context, name string, src io.Reader, size int64) error
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	List(ctx context.Context) ([]Object, error)
+	Delete(ctx context.Context, name string) error
+	Close() error
+}
+
+// New connects to the target, caller must close it.
+func New(ctx context.Context, t *entity.BackupTarget) (Target, error) {
+	switch t.Kind {
+	case entity.BackupTargetLocal:
+		return newLocal(t)
+	case entity.BackupTargetSFTP:
+		return newSFTP(ctx, t)
+	case entity.BackupTargetS3:
+		return newS3(ctx, t)
+	default:
+		return nil, fmt.Errorf("unknown target kind %q", t.Kind)
+	}
+}
+
+// Validate checks the fields required by the kind, secrets are checked after merged.
+func Validate(t *entity.BackupTarget) error {
+	if t.Name == "" {
+		return errors.New("name required")
+	}
+	if t.KeepCount < 0 || t.KeepDays < 0 {
+		return errors.New("keep must not be negative")
+	}
+	switch t.Kind {
+	case entity.BackupTargetLocal:
+		if !filepath.IsAbs(t.Path) {
+			return errors.New("local path must be absolute")
+		}
+	case entity.BackupTargetSFTP:
+		if t.Host == "" || t.User == "" || t.Path == "" {
+			return errors.New("sftp host, user and path required")
+		}
+		if t.HostKey == "" {
+			return errors.New("sftp host key required")
+		}
+		if t.Password == "" && t.PrivateKey == "" {
+			return errors.New("sftp password or private key required")
+		}
+	case entity.BackupTargetS3:
+		if t.Endpoint == "" || t.Bucket == "" || t.AccessKey == "" || t.SecretKey == "" {
+			return errors.New("s3 endpoint, bucket, access key and secret key required")
+		}
+	default:
+		return fmt.Errorf("unknown target kind %q", t.Kind)
+	}
+	return nil
+}
+
+// checkName rejects the names escaping the target path.
+func checkName(name string) error {
+	if name == "" || name != path.Base(name) || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return fmt.Errorf("invalid object name %q", name)
+	}
+	return nil
+}
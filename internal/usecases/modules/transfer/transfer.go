@@ -0,0 +1,284 @@
+// Package transfer writes and reads the export files of one domain in json, ndjson or protobuf.
+//
+// Every file starts with the header, then the records. The json is indented and ndjson is one
+// record per line, both sorted by the caller, so two exports can be diffed. Protobuf is only for
+// the records defined in pb, each message is prefixed by its size.
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/chindada/capitan/internal/usecases/entity"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	// Magic is the first field of the header.
+	Magic = "capitan-export"
+	// Version is the file layout written, files of a newer version are rejected.
+	Version = 1
+)
+
+// maxMessageSize limits a protobuf record, a record is a row, not a table.
+const maxMessageSize = 16 << 20
+
+var (
+	ErrInvalid     = errors.New("invalid export file")
+	ErrUnsupported = errors.New("format not supported by the records")
+)
+
+// Ext is the file extension of format.
+func Ext(format entity.TransferFormat) string {
+	switch format {
+	case entity.TransferFormatNDJSON:
+		return ".ndjson"
+	case entity.TransferFormatProtobuf:
+		return ".pb"
+	default:
+		return ".json"
+	}
+}
+
+// ContentType is the media type of format.
+func ContentType(format entity.TransferFormat) string {
+	switch format {
+	case entity.TransferFormatNDJSON:
+		return "application/x-ndjson"
+	case entity.TransferFormatProtobuf:
+		return "application/x-protobuf"
+	default:
+		return "application/json"
+	}
+}
+
+// Valid is false if format is unknown.
+func Valid(format entity.TransferFormat) bool {
+	switch format {
+	case entity.TransferFormatJSON, entity.TransferFormatNDJSON, entity.TransferFormatProtobuf:
+		return true
+	default:
+		return false
+	}
+}
+
+// Writer writes the header at once, the records by Write, Close ends the file.
+type Writer struct {
+	w      *bufio.Writer
+	format entity.TransferFormat
+	count  int
+}
+
+func NewWriter(w io.Writer, format entity.TransferFormat, header *entity.TransferHeader) (*Writer, error) {
+	if !Valid(format) {
+		return nil, ErrUnsupported
+	}
+	header.Magic = Magic
+	header.Version = Version
+	tw := &Writer{w: bufio.NewWriter(w), format: format}
+	data, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case entity.TransferFormatJSON:
+		var buf bytes.Buffer
+		if err = json.Indent(&buf, data, "  ", "  "); err != nil {
+			return nil, err
+		}
+		_, err = fmt.Fprintf(tw.w, "{\n  \"header\": %s,\n  \"records\": [", buf.Bytes())
+	case entity.TransferFormatNDJSON:
+		_, err = fmt.Fprintf(tw.w, "%s\n", data)
+	case entity.TransferFormatProtobuf:
+		_, err = protodelim.MarshalTo(tw.w, wrapperspb.Bytes(data))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tw, nil
+}
+
+func (tw *Writer) Write(record any) error {
+	if tw.format == entity.TransferFormatProtobuf {
+		m, ok := record.(proto.Message)
+		if !ok {
+			return ErrUnsupported
+		}
+		_, err := protodelim.MarshalTo(tw.w, m)
+		return err
+	}
+	data, err := marshal(record)
+	if err != nil {
+		return err
+	}
+	if tw.format == entity.TransferFormatNDJSON {
+		_, err = fmt.Fprintf(tw.w, "%s\n", data)
+		return err
+	}
+	var buf bytes.Buffer
+	if err = json.Indent(&buf, data, "    ", "  "); err != nil {
+		return err
+	}
+	sep := ","
+	if tw.count == 0 {
+		sep = ""
+	}
+	tw.count++
+	_, err = fmt.Fprintf(tw.w, "%s\n    %s", sep, buf.Bytes())
+	return err
+}
+
+// Close flushes the file, the underlying writer is not closed.
+func (tw *Writer) Close() error {
+	if tw.format == entity.TransferFormatJSON {
+		end := "]\n}\n"
+		if tw.count > 0 {
+			end = "\n  ]\n}\n"
+		}
+		if _, err := tw.w.WriteString(end); err != nil {
+			return err
+		}
+	}
+	return tw.w.Flush()
+}
+
+// Reader checks the header at once, Next reads the records until io.EOF.
+type Reader struct {
+	format entity.TransferFormat
+	header entity.TransferHeader
+	dec    *json.Decoder
+	buf    *bufio.Reader
+}
+
+func NewReader(r io.Reader, format entity.TransferFormat) (*Reader, error) {
+	if !Valid(format) {
+		return nil, ErrUnsupported
+	}
+	tr := &Reader{format: format}
+	var err error
+	switch format {
+	case entity.TransferFormatJSON:
+		err = tr.openJSON(r)
+	case entity.TransferFormatNDJSON:
+		tr.dec = json.NewDecoder(r)
+		err = tr.dec.Decode(&tr.header)
+	case entity.TransferFormatProtobuf:
+		tr.buf = bufio.NewReader(r)
+		data := &wrapperspb.BytesValue{}
+		if err = (protodelim.UnmarshalOptions{MaxSize: maxMessageSize}).UnmarshalFrom(tr.buf, data); err == nil {
+			err = json.Unmarshal(data.GetValue(), &tr.header)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: header: %w", ErrInvalid, err)
+	}
+	if tr.header.Magic != Magic {
+		return nil, fmt.Errorf("%w: not an export", ErrInvalid)
+	}
+	if tr.header.Version < 1 || tr.header.Version > Version {
+		return nil, fmt.Errorf("%w: version %d not supported", ErrInvalid, tr.header.Version)
+	}
+	return tr, nil
+}
+
+// openJSON reads until the first record, the header must come before the records.
+func (tr *Reader) openJSON(r io.Reader) error {
+	tr.dec = json.NewDecoder(r)
+	if err := expectToken(tr.dec, json.Delim('{')); err != nil {
+		return err
+	}
+	if err := expectToken(tr.dec, "header"); err != nil {
+		return err
+	}
+	if err := tr.dec.Decode(&tr.header); err != nil {
+		return err
+	}
+	if err := expectToken(tr.dec, "records"); err != nil {
+		return err
+	}
+	return expectToken(tr.dec, json.Delim('['))
+}
+
+func expectToken(dec *json.Decoder, want json.Token) error {
+	got, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("expect %v, got %v", want, got)
+	}
+	return nil
+}
+
+func (tr *Reader) Header() *entity.TransferHeader {
+	return &tr.header
+}
+
+// Next decodes the next record into record, io.EOF after the last one.
+func (tr *Reader) Next(record any) error {
+	switch tr.format {
+	case entity.TransferFormatProtobuf:
+		m, ok := record.(proto.Message)
+		if !ok {
+			return ErrUnsupported
+		}
+		err := (protodelim.UnmarshalOptions{MaxSize: maxMessageSize}).UnmarshalFrom(tr.buf, m)
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+		return invalid(err)
+	case entity.TransferFormatJSON:
+		if !tr.dec.More() {
+			if err := expectToken(tr.dec, json.Delim(']')); err != nil {
+				return invalid(err)
+			}
+			return io.EOF
+		}
+	}
+	var data json.RawMessage
+	if err := tr.dec.Decode(&data); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+		return invalid(err)
+	}
+	return invalid(unmarshal(data, record))
+}
+
+func invalid(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrInvalid, err)
+}
+
+// marshal keeps the field names of the proto file, protojson output is compacted as it is not stable.
+func marshal(record any) ([]byte, error) {
+	m, ok := record.(proto.Message)
+	if !ok {
+		return json.Marshal(record)
+	}
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = json.Compact(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func unmarshal(data []byte, record any) error {
+	if m, ok := record.(proto.Message); ok {
+		return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, m)
+	}
+	return json.Unmarshal(data, record)
+}
@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chindada/capitan/internal/usecases/repo/migrations"
+	"github.com/chindada/panther/pkg/client"
+	"github.com/jackc/pgx/v5"
+)
+
+//go:generate mockgen -source=backup_postgres.go -destination=./mocks/mocks_backup_postgres_test.go -package=mocks
+
+type BackupRepo interface {
+	CountRows(ctx context.Context) (map[string]int64, error)
+	SelectSchemaVersion(ctx context.Context) (int, int, error)
+
+	CreateDatabase(ctx context.Context, name string) error
+	DropDatabase(ctx context.Context, name string) error
+}
+
+type backup struct {
+	client.PGClient
+}
+
+func NewBackupRepo(pg client.PGClient) BackupRepo {
+	return &backup{pg}
+}
+
+// CountRows returns the exact row count of every table in public schema.
+func (r *backup) CountRows(ctx context.Context) (map[string]int64, error) {
+	ctx, span := startSpan(ctx, "BackupRepo.CountRows")
+	defer span.End()
+	sql, args, err := r.Builder().
+		Select("tablename").
+		From("pg_tables").
+		Where("schemaname = 'public'").
+		OrderBy("tablename").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.Pool().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]int64, len(tables))
+	for _, t := range tables {
+		var count int64
+		if err = r.Pool().QueryRow(ctx,
+			fmt.Sprintf("SELECT count(*) FROM %s", pgx.Identifier{t}.Sanitize()),
+		).Scan(&count); err != nil {
+			return nil, err
+		}
+		result[t] = count
+	}
+	return result, nil
+}
+
+// SelectSchemaVersion returns the migration version of panther and capitan, 0 if never migrated.
+func (r *backup) SelectSchemaVersion(ctx context.Context) (int, int, error) {
+	ctx, span := startSpan(ctx, "BackupRepo.SelectSchemaVersion")
+	defer span.End()
+	core, err := r.selectMigration(ctx, tableNameSchemaMigrations)
+	if err != nil {
+		return 0, 0, err
+	}
+	local, err := r.selectMigration(ctx, migrations.Table)
+	if err != nil {
+		return 0, 0, err
+	}
+	return core, local, nil
+}
+
+func (r *backup) selectMigration(ctx context.Context, table string) (int, error) {
+	sql, args, err := r.Builder().
+		Select("version").
+		From(table).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+	var version int
+	if err = r.Pool().QueryRow(ctx, sql, args...).Scan(&version); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return version, nil
+}
+
+// CreateDatabase can not run in transaction.
+func (r *backup) CreateDatabase(ctx context.Context, name string) error {
+	ctx, span := startSpan(ctx, "BackupRepo.CreateDatabase")
+	defer span.End()
+	_, err := r.Pool().Exec(ctx, fmt.Sprintf("CREATE DATABASE %s", pgx.Identifier{name}.Sanitize()))
+	return err
+}
+
+// DropDatabase terminates the connections to name first.
+func (r *backup) DropDatabase(ctx context.Context, name string) error {
+	ctx, span := startSpan(ctx, "BackupRepo.DropDatabase")
+	defer span.End()
+	_, err := r.Pool().Exec(ctx, fmt.Sprintf("DROP DATABASE IF EXISTS %s WITH (FORCE)", pgx.Identifier{name}.Sanitize()))
+	return err
+}
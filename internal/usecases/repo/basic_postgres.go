@@ -3,19 +3,30 @@ package repo
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/Masterminds/squirrel"
 	"github.com/chindada/capitan/internal/usecases/entity"
 	"github.com/chindada/panther/golang/pb"
 	"github.com/chindada/panther/pkg/client"
+	"github.com/jackc/pgx/v5"
 )
 
 //go:generate mockgen -source=basic_postgres.go -destination=./mocks/mocks_basic_postgres_test.go -package=mocks
 
 type BasicRepo interface {
-	InsertStockDetail(ctx context.Context, t []*pb.StockDetail) error
-	InsertFutureDetail(ctx context.Context, t []*pb.FutureDetail) error
-	InsertOptionDetail(ctx context.Context, t []*pb.OptionDetail) error
+	ImportStockDetail(ctx context.Context, t []*entity.StockRow, removed []string) error
+	ImportFutureDetail(ctx context.Context, t []*entity.FutureRow, removed []string) error
+	ImportOptionDetail(ctx context.Context, t []*entity.OptionRow, removed []string) error
+	MergeStockDetail(ctx context.Context, t []*entity.StockRow) error
+	SelectInstrumentCodes(ctx context.Context, kind entity.InstrumentKind) ([]string, error)
+
+	SelectFutureDetail(ctx context.Context, filter *entity.ContractFilter) ([]*pb.FutureDetail, error)
+	SelectOptionDetail(ctx context.Context, filter *entity.ContractFilter) ([]*pb.OptionDetail, error)
+	SelectActiveStockDetail(ctx context.Context) ([]*pb.StockDetail, error)
+
+	SelectPriceHistory(ctx context.Context, kind entity.InstrumentKind, code string, from, to time.Time) ([]*entity.PriceHistory, error)
 }
 
 type basic struct {
@@ -26,6 +37,97 @@ func NewBasic(pg client.PGClient) BasicRepo {
 	return &basic{pg}
 }
 
+func instrumentTable(kind entity.InstrumentKind) (string, error) {
+	switch kind {
+	case entity.InstrumentKindStock:
+		return tableNameBasicStock, nil
+	case entity.InstrumentKindFuture:
+		return tableNameBasicFuture, nil
+	case entity.InstrumentKindOption:
+		return tableNameBasicOption, nil
+	default:
+		return "", errUnknownInstrumentKind
+	}
+}
+
+// instrumentImport is one ingestion of a kind, rows are in the same order of columns.
+type instrumentImport struct {
+	kind      entity.InstrumentKind
+	table     string
+	tmpTable  string
+	tmpSchema string
+	columns   []string
+	rows      [][]any
+	merge     string
+	reference string
+	removed   []string
+	// noHistory skips the price history, the rows have no limits.
+	noHistory bool
+}
+
+// importInstrument streams rows into a temp table with COPY, then merges into the target table,
+// marks removed codes inactive and appends the price history unless noHistory, all in one transaction.
+func (r *basic) importInstrument(ctx context.Context, imp *instrumentImport) error {
+	tx, err := r.Pool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer r.Rollback(ctx, tx)
+
+	if _, err = tx.Exec(ctx, fmt.Sprintf("CREATE TEMP TABLE %s(%s) ON COMMIT DROP", imp.tmpTable, imp.tmpSchema)); err != nil {
+		return err
+	}
+	if _, err = tx.CopyFrom(ctx, pgx.Identifier{imp.tmpTable}, imp.columns, pgx.CopyFromRows(imp.rows)); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, imp.merge); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if len(imp.removed) > 0 {
+		sql, args, bErr := r.Builder().
+			Update(imp.table).
+			Set("active", false).
+			Set("inactive_at", now).
+			Where(squirrel.Eq{"code": imp.removed, "active": true}).
+			ToSql()
+		if bErr != nil {
+			return bErr
+		}
+		if _, err = tx.Exec(ctx, sql, args...); err != nil {
+			return err
+		}
+	}
+
+	if imp.noHistory {
+		return tx.Commit(ctx)
+	}
+	sql, args, err := r.Builder().
+		Insert(tableNameBasicPriceHistory).
+		Columns("code", "kind", "update_date", "reference", "limit_up", "limit_down", "created_at").
+		Select(
+			r.Builder().
+				Select("code", "?", "trade_date", imp.reference, "limit_up", "limit_down", "?::timestamptz").
+				From(imp.tmpTable),
+		).
+		Suffix("ON CONFLICT (code, kind, update_date) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, sql, append([]any{string(imp.kind), now}, args...)...); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// tradeDate is the local date of t, the update date is stored in timestamptz.
+func tradeDate(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
 // CREATE TABLE basic_stock(
 //     "code" varchar PRIMARY KEY,
 //     "name" varchar NOT NULL,
@@ -33,55 +135,63 @@ func NewBasic(pg client.PGClient) BasicRepo {
 //     "category" varchar NOT NULL,
 //     "day_trade" boolean NOT NULL,
 //     "last_close" DECIMAL NOT NULL,
-//     "update_date" timestamptz NOT NULL
+//     "update_date" timestamptz NOT NULL,
+//     "active" boolean NOT NULL DEFAULT TRUE,
+//     "inactive_at" timestamptz DEFAULT NULL
 // );
 
-func (r *basic) InsertStockDetail(ctx context.Context, t []*pb.StockDetail) error {
-	builder := r.Builder().
-		Insert(tableNameBasicStock).
-		Columns(
-			"code", "name", "exchange", "category", "day_trade", "last_close", "update_date",
-		)
-
+func stockImport(t []*entity.StockRow) *instrumentImport {
+	rows := make([][]any, 0, len(t))
 	for _, item := range t {
-		updateTime, err := time.ParseInLocation(entity.ShortSlashTimeLayout, item.GetUpdateDate(), time.Local)
-		if err != nil {
-			return err
-		}
-		builder = builder.Values(
-			item.GetCode(),
-			item.GetName(),
-			item.GetExchange(),
-			item.GetCategory(),
-			item.GetDayTrade() == entity.DayTradeYes,
-			item.GetReference(),
-			updateTime,
-		)
-	}
-	builder = builder.Suffix(`ON CONFLICT (code) DO UPDATE SET
+		rows = append(rows, []any{
+			item.Code, item.Name, item.Exchange, item.Category, item.DayTrade,
+			item.Reference, item.LimitUp, item.LimitDown, item.UpdateDate, tradeDate(item.UpdateDate),
+		})
+	}
+	return &instrumentImport{
+		kind:     entity.InstrumentKindStock,
+		table:    tableNameBasicStock,
+		tmpTable: "tmp_" + tableNameBasicStock,
+		tmpSchema: `"code" varchar NOT NULL, "name" varchar NOT NULL, "exchange" varchar NOT NULL,
+			"category" varchar NOT NULL, "day_trade" boolean NOT NULL, "last_close" DECIMAL NOT NULL,
+			"limit_up" DECIMAL NOT NULL, "limit_down" DECIMAL NOT NULL,
+			"update_date" timestamptz NOT NULL, "trade_date" date NOT NULL`,
+		columns: []string{
+			"code", "name", "exchange", "category", "day_trade",
+			"last_close", "limit_up", "limit_down", "update_date", "trade_date",
+		},
+		rows: rows,
+		merge: `INSERT INTO basic_stock (code, name, exchange, category, day_trade, last_close, update_date)
+			SELECT code, name, exchange, category, day_trade, last_close, update_date FROM tmp_basic_stock
+			ON CONFLICT (code) DO UPDATE SET
             name = EXCLUDED.name,
             exchange = EXCLUDED.exchange,
             category = EXCLUDED.category,
             day_trade = EXCLUDED.day_trade,
             last_close = EXCLUDED.last_close,
-            update_date = EXCLUDED.update_date
-        `)
-
-	sql, args, err := builder.ToSql()
-	if err != nil {
-		return err
+            update_date = EXCLUDED.update_date,
+            active = TRUE,
+            inactive_at = NULL
+        `,
+		reference: "last_close",
 	}
+}
 
-	tx, err := r.Pool().Begin(ctx)
-	if err != nil {
-		return err
-	}
-	defer r.Rollback(ctx, tx)
+func (r *basic) ImportStockDetail(ctx context.Context, t []*entity.StockRow, removed []string) error {
+	ctx, span := startSpan(ctx, "BasicRepo.ImportStockDetail")
+	defer span.End()
+	imp := stockImport(t)
+	imp.removed = removed
+	return r.importInstrument(ctx, imp)
+}
 
-	if _, err = tx.Exec(ctx, sql, args...); err != nil {
-		return err
-	}
-	return tx.Commit(ctx)
+// MergeStockDetail upserts the rows without the price history, no stock is marked removed.
+func (r *basic) MergeStockDetail(ctx context.Context, t []*entity.StockRow) error {
+	ctx, span := startSpan(ctx, "BasicRepo.MergeStockDetail")
+	defer span.End()
+	imp := stockImport(t)
+	imp.noHistory = true
+	return r.importInstrument(ctx, imp)
 }
 
 // CREATE TABLE basic_future(
@@ -96,42 +206,49 @@ func (r *basic) InsertStockDetail(ctx context.Context, t []*pb.StockDetail) erro
 //     "limit_up" DECIMAL NOT NULL,
 //     "limit_down" DECIMAL NOT NULL,
 //     "reference" DECIMAL NOT NULL,
-//     "update_date" timestamptz NOT NULL
+//     "update_date" timestamptz NOT NULL,
+//     "active" boolean NOT NULL DEFAULT TRUE,
+//     "inactive_at" timestamptz DEFAULT NULL
 // );
 
-func (r *basic) InsertFutureDetail(ctx context.Context, t []*pb.FutureDetail) error {
-	builder := r.Builder().
-		Insert(tableNameBasicFuture).
-		Columns(
-			"code", "symbol", "name", "category", "delivery_month", "delivery_date",
-			"underlying_kind", "unit", "limit_up", "limit_down", "reference", "update_date",
-		)
+func futureRowValues(item *entity.FutureRow) []any {
+	return []any{
+		item.Code, item.Symbol, item.Name, item.Category, item.DeliveryMonth, item.DeliveryDate,
+		item.UnderlyingKind, item.Unit, item.LimitUp, item.LimitDown, item.Reference,
+		item.UpdateDate, tradeDate(item.UpdateDate),
+	}
+}
+
+const futureTmpSchema = `"code" varchar NOT NULL, "symbol" varchar NOT NULL, "name" varchar NOT NULL,
+	"category" varchar NOT NULL, "delivery_month" varchar NOT NULL, "delivery_date" timestamptz NOT NULL,
+	"underlying_kind" varchar NOT NULL, "unit" int NOT NULL,
+	"limit_up" DECIMAL NOT NULL, "limit_down" DECIMAL NOT NULL, "reference" DECIMAL NOT NULL,
+	"update_date" timestamptz NOT NULL, "trade_date" date NOT NULL`
+
+var futureTmpColumns = []string{
+	"code", "symbol", "name", "category", "delivery_month", "delivery_date",
+	"underlying_kind", "unit", "limit_up", "limit_down", "reference", "update_date", "trade_date",
+}
 
+func (r *basic) ImportFutureDetail(ctx context.Context, t []*entity.FutureRow, removed []string) error {
+	ctx, span := startSpan(ctx, "BasicRepo.ImportFutureDetail")
+	defer span.End()
+	rows := make([][]any, 0, len(t))
 	for _, item := range t {
-		updateTime, err := time.ParseInLocation(entity.ShortSlashTimeLayout, item.GetUpdateDate(), time.Local)
-		if err != nil {
-			return err
-		}
-		dDate, e := time.ParseInLocation(entity.ShortSlashTimeLayout, item.GetDeliveryDate(), time.Local)
-		if e != nil {
-			continue
-		}
-		builder = builder.Values(
-			item.GetCode(),
-			item.GetSymbol(),
-			item.GetName(),
-			item.GetCategory(),
-			item.GetDeliveryMonth(),
-			dDate.Add(810*time.Minute),
-			item.GetUnderlyingKind(),
-			item.GetUnit(),
-			item.GetLimitUp(),
-			item.GetLimitDown(),
-			item.GetReference(),
-			updateTime,
-		)
-	}
-	builder = builder.Suffix(`ON CONFLICT (code) DO UPDATE SET
+		rows = append(rows, futureRowValues(item))
+	}
+	return r.importInstrument(ctx, &instrumentImport{
+		kind:      entity.InstrumentKindFuture,
+		table:     tableNameBasicFuture,
+		tmpTable:  "tmp_" + tableNameBasicFuture,
+		tmpSchema: futureTmpSchema,
+		columns:   futureTmpColumns,
+		rows:      rows,
+		merge: `INSERT INTO basic_future (code, symbol, name, category, delivery_month, delivery_date,
+			underlying_kind, unit, limit_up, limit_down, reference, update_date)
+			SELECT code, symbol, name, category, delivery_month, delivery_date,
+			underlying_kind, unit, limit_up, limit_down, reference, update_date FROM tmp_basic_future
+			ON CONFLICT (code) DO UPDATE SET
 			symbol = EXCLUDED.symbol,
             name = EXCLUDED.name,
 			category = EXCLUDED.category,
@@ -142,24 +259,13 @@ func (r *basic) InsertFutureDetail(ctx context.Context, t []*pb.FutureDetail) er
 			limit_up = EXCLUDED.limit_up,
 			limit_down = EXCLUDED.limit_down,
 			reference = EXCLUDED.reference,
-			update_date = EXCLUDED.update_date
-        `)
-
-	sql, args, err := builder.ToSql()
-	if err != nil {
-		return err
-	}
-
-	tx, err := r.Pool().Begin(ctx)
-	if err != nil {
-		return err
-	}
-	defer r.Rollback(ctx, tx)
-
-	if _, err = tx.Exec(ctx, sql, args...); err != nil {
-		return err
-	}
-	return tx.Commit(ctx)
+			update_date = EXCLUDED.update_date,
+			active = TRUE,
+			inactive_at = NULL
+        `,
+		reference: "reference",
+		removed:   removed,
+	})
 }
 
 // CREATE TABLE basic_option(
@@ -176,45 +282,31 @@ func (r *basic) InsertFutureDetail(ctx context.Context, t []*pb.FutureDetail) er
 //     "limit_up" DECIMAL NOT NULL,
 //     "limit_down" DECIMAL NOT NULL,
 //     "reference" DECIMAL NOT NULL,
-//     "update_date" timestamptz NOT NULL
+//     "update_date" timestamptz NOT NULL,
+//     "active" boolean NOT NULL DEFAULT TRUE,
+//     "inactive_at" timestamptz DEFAULT NULL
 // );
 
-func (r *basic) InsertOptionDetail(ctx context.Context, t []*pb.OptionDetail) error {
-	builder := r.Builder().
-		Insert(tableNameBasicOption).
-		Columns(
-			"code", "symbol", "name", "category", "delivery_month", "delivery_date",
-			"strike_price", "option_right",
-			"underlying_kind", "unit", "limit_up", "limit_down", "reference", "update_date",
-		)
-
+func (r *basic) ImportOptionDetail(ctx context.Context, t []*entity.OptionRow, removed []string) error {
+	ctx, span := startSpan(ctx, "BasicRepo.ImportOptionDetail")
+	defer span.End()
+	rows := make([][]any, 0, len(t))
 	for _, item := range t {
-		updateTime, err := time.ParseInLocation(entity.ShortSlashTimeLayout, item.GetUpdateDate(), time.Local)
-		if err != nil {
-			return err
-		}
-		dDate, e := time.ParseInLocation(entity.ShortSlashTimeLayout, item.GetDeliveryDate(), time.Local)
-		if e != nil {
-			continue
-		}
-		builder = builder.Values(
-			item.GetCode(),
-			item.GetSymbol(),
-			item.GetName(),
-			item.GetCategory(),
-			item.GetDeliveryMonth(),
-			dDate.Add(810*time.Minute),
-			item.GetStrikePrice(),
-			item.GetOptionRight(),
-			item.GetUnderlyingKind(),
-			item.GetUnit(),
-			item.GetLimitUp(),
-			item.GetLimitDown(),
-			item.GetReference(),
-			updateTime,
-		)
-	}
-	builder = builder.Suffix(`ON CONFLICT (code) DO UPDATE SET
+		rows = append(rows, append(futureRowValues(&item.FutureRow), item.StrikePrice, item.OptionRight))
+	}
+	return r.importInstrument(ctx, &instrumentImport{
+		kind:      entity.InstrumentKindOption,
+		table:     tableNameBasicOption,
+		tmpTable:  "tmp_" + tableNameBasicOption,
+		tmpSchema: futureTmpSchema + `, "strike_price" DECIMAL NOT NULL, "option_right" varchar NOT NULL`,
+		columns:   append(append([]string{}, futureTmpColumns...), "strike_price", "option_right"),
+		rows:      rows,
+		merge: `INSERT INTO basic_option (code, symbol, name, category, delivery_month, delivery_date,
+			strike_price, option_right, underlying_kind, unit, limit_up, limit_down, reference, update_date)
+			SELECT code, symbol, name, category, delivery_month, delivery_date,
+			strike_price, option_right, underlying_kind, unit, limit_up, limit_down, reference, update_date
+			FROM tmp_basic_option
+			ON CONFLICT (code) DO UPDATE SET
 			symbol = EXCLUDED.symbol,
             name = EXCLUDED.name,
 			category = EXCLUDED.category,
@@ -227,22 +319,234 @@ func (r *basic) InsertOptionDetail(ctx context.Context, t []*pb.OptionDetail) er
 			limit_up = EXCLUDED.limit_up,
 			limit_down = EXCLUDED.limit_down,
 			reference = EXCLUDED.reference,
-			update_date = EXCLUDED.update_date
-        `)
+			update_date = EXCLUDED.update_date,
+			active = TRUE,
+			inactive_at = NULL
+        `,
+		reference: "reference",
+		removed:   removed,
+	})
+}
 
-	sql, args, err := builder.ToSql()
+// contractFilter only contains the non-empty fields of filter, option_right is only for options.
+// Inactive contracts are always excluded, expired ones are excluded unless IncludeExpired.
+func contractFilter(filter *entity.ContractFilter, withOptionRight bool) squirrel.And {
+	if filter == nil {
+		filter = &entity.ContractFilter{}
+	}
+	eq := squirrel.Eq{"active": true}
+	if filter.UnderlyingKind != "" {
+		eq["underlying_kind"] = filter.UnderlyingKind
+	}
+	if filter.DeliveryMonth != "" {
+		eq["delivery_month"] = filter.DeliveryMonth
+	}
+	if filter.Category != "" {
+		eq["category"] = filter.Category
+	}
+	if withOptionRight && filter.OptionRight != "" {
+		eq["option_right"] = filter.OptionRight
+	}
+	if filter.IncludeExpired {
+		return squirrel.And{eq}
+	}
+	return squirrel.And{eq, squirrel.Gt{"delivery_date": time.Now()}}
+}
+
+func (r *basic) SelectFutureDetail(ctx context.Context, filter *entity.ContractFilter) ([]*pb.FutureDetail, error) {
+	ctx, span := startSpan(ctx, "BasicRepo.SelectFutureDetail")
+	defer span.End()
+	sql, args, err := r.Builder().
+		Select(
+			"code, symbol, name, category, delivery_month, delivery_date, "+
+				"underlying_kind, unit, limit_up, limit_down, reference, update_date",
+		).
+		From(tableNameBasicFuture).
+		Where(contractFilter(filter, false)).
+		OrderBy("delivery_date ASC", "code ASC").
+		ToSql()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	tx, err := r.Pool().Begin(ctx)
+	rows, err := r.Pool().Query(ctx, sql, args...)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer r.Rollback(ctx, tx)
+	defer rows.Close()
 
-	if _, err = tx.Exec(ctx, sql, args...); err != nil {
-		return err
+	var result []*pb.FutureDetail
+	for rows.Next() {
+		e := pb.FutureDetail{}
+		var deliveryDate, updateDate time.Time
+		if err = rows.Scan(
+			&e.Code, &e.Symbol, &e.Name, &e.Category, &e.DeliveryMonth, &deliveryDate,
+			&e.UnderlyingKind, &e.Unit, &e.LimitUp, &e.LimitDown, &e.Reference, &updateDate,
+		); err != nil {
+			return nil, err
+		}
+		e.DeliveryDate = deliveryDate.Local().Format(entity.ShortSlashTimeLayout)
+		e.UpdateDate = updateDate.Local().Format(entity.ShortSlashTimeLayout)
+		result = append(result, &e)
 	}
-	return tx.Commit(ctx)
+	return result, rows.Err()
+}
+
+func (r *basic) SelectOptionDetail(ctx context.Context, filter *entity.ContractFilter) ([]*pb.OptionDetail, error) {
+	ctx, span := startSpan(ctx, "BasicRepo.SelectOptionDetail")
+	defer span.End()
+	sql, args, err := r.Builder().
+		Select(
+			"code, symbol, name, category, delivery_month, delivery_date, strike_price, option_right, "+
+				"underlying_kind, unit, limit_up, limit_down, reference, update_date",
+		).
+		From(tableNameBasicOption).
+		Where(contractFilter(filter, true)).
+		OrderBy("delivery_date ASC", "strike_price ASC", "code ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.Pool().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*pb.OptionDetail
+	for rows.Next() {
+		e := pb.OptionDetail{}
+		var deliveryDate, updateDate time.Time
+		if err = rows.Scan(
+			&e.Code, &e.Symbol, &e.Name, &e.Category, &e.DeliveryMonth, &deliveryDate, &e.StrikePrice, &e.OptionRight,
+			&e.UnderlyingKind, &e.Unit, &e.LimitUp, &e.LimitDown, &e.Reference, &updateDate,
+		); err != nil {
+			return nil, err
+		}
+		e.DeliveryDate = deliveryDate.Local().Format(entity.ShortSlashTimeLayout)
+		e.UpdateDate = updateDate.Local().Format(entity.ShortSlashTimeLayout)
+		result = append(result, &e)
+	}
+	return result, rows.Err()
+}
+
+func (r *basic) SelectActiveStockDetail(ctx context.Context) ([]*pb.StockDetail, error) {
+	ctx, span := startSpan(ctx, "BasicRepo.SelectActiveStockDetail")
+	defer span.End()
+	sql, args, err := r.Builder().
+		Select("code, name, exchange, category, day_trade, last_close, update_date").
+		From(tableNameBasicStock).
+		Where(squirrel.Eq{"active": true}).
+		OrderBy("code ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.Pool().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*pb.StockDetail
+	for rows.Next() {
+		e := pb.StockDetail{}
+		var dayTrade bool
+		var updateDate time.Time
+		if err = rows.Scan(
+			&e.Code, &e.Name, &e.Exchange, &e.Category, &dayTrade, &e.Reference, &updateDate,
+		); err != nil {
+			return nil, err
+		}
+		e.DayTrade = entity.DayTradeNo
+		if dayTrade {
+			e.DayTrade = entity.DayTradeYes
+		}
+		e.UpdateDate = updateDate.Local().Format(entity.ShortSlashTimeLayout)
+		result = append(result, &e)
+	}
+	return result, rows.Err()
+}
+
+// SelectInstrumentCodes returns the codes of kind, the inactive ones included.
+func (r *basic) SelectInstrumentCodes(ctx context.Context, kind entity.InstrumentKind) ([]string, error) {
+	ctx, span := startSpan(ctx, "BasicRepo.SelectInstrumentCodes")
+	defer span.End()
+	table, err := instrumentTable(kind)
+	if err != nil {
+		return nil, err
+	}
+	sql, args, err := r.Builder().
+		Select("code").
+		From(table).
+		OrderBy("code ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.Pool().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []string{}
+	for rows.Next() {
+		var code string
+		if err = rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		result = append(result, code)
+	}
+	return result, rows.Err()
+}
+
+// CREATE TABLE basic_price_history(
+//     "code" varchar NOT NULL,
+//     "kind" varchar NOT NULL,
+//     "update_date" date NOT NULL,
+//     "reference" DECIMAL NOT NULL,
+//     "limit_up" DECIMAL NOT NULL,
+//     "limit_down" DECIMAL NOT NULL,
+//     "created_at" timestamptz NOT NULL,
+//     PRIMARY KEY ("code", "kind", "update_date")
+// );
+
+func (r *basic) SelectPriceHistory(
+	ctx context.Context, kind entity.InstrumentKind, code string, from, to time.Time,
+) ([]*entity.PriceHistory, error) {
+	ctx, span := startSpan(ctx, "BasicRepo.SelectPriceHistory")
+	defer span.End()
+	sql, args, err := r.Builder().
+		Select("code, kind, update_date, reference, limit_up, limit_down").
+		From(tableNameBasicPriceHistory).
+		Where(squirrel.Eq{"code": code, "kind": string(kind)}).
+		Where(squirrel.GtOrEq{"update_date": from}).
+		Where(squirrel.LtOrEq{"update_date": to}).
+		OrderBy("update_date ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.Pool().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*entity.PriceHistory{}
+	for rows.Next() {
+		e := entity.PriceHistory{}
+		var kind string
+		if err = rows.Scan(&e.Code, &kind, &e.UpdateDate, &e.Reference, &e.LimitUp, &e.LimitDown); err != nil {
+			return nil, err
+		}
+		e.Kind = entity.InstrumentKind(kind)
+		result = append(result, &e)
+	}
+	return result, rows.Err()
 }
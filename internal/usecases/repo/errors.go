@@ -9,4 +9,7 @@ func (e *Error) Error() string {
 	return e.Message
 }
 
-var errInsertFail = &Error{Code: -31, Message: "insert fail"}
+var (
+	errInsertFail            = &Error{Code: -31, Message: "insert fail"}
+	errUnknownInstrumentKind = &Error{Code: -32, Message: "unknown instrument kind"}
+)
@@ -0,0 +1,5 @@
+BEGIN;
+ALTER TABLE basic_stock DROP COLUMN "active", DROP COLUMN "inactive_at";
+ALTER TABLE basic_future DROP COLUMN "active", DROP COLUMN "inactive_at";
+ALTER TABLE basic_option DROP COLUMN "active", DROP COLUMN "inactive_at";
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+ALTER TABLE basic_stock
+    ADD COLUMN "active" boolean NOT NULL DEFAULT TRUE,
+    ADD COLUMN "inactive_at" timestamptz DEFAULT NULL;
+ALTER TABLE basic_future
+    ADD COLUMN "active" boolean NOT NULL DEFAULT TRUE,
+    ADD COLUMN "inactive_at" timestamptz DEFAULT NULL;
+ALTER TABLE basic_option
+    ADD COLUMN "active" boolean NOT NULL DEFAULT TRUE,
+    ADD COLUMN "inactive_at" timestamptz DEFAULT NULL;
+COMMIT;
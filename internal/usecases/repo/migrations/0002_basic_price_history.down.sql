@@ -0,0 +1,3 @@
+BEGIN;
+DROP TABLE IF EXISTS basic_price_history;
+COMMIT;
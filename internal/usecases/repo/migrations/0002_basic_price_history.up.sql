@@ -0,0 +1,12 @@
+BEGIN;
+CREATE TABLE basic_price_history(
+    "code" varchar NOT NULL,
+    "kind" varchar NOT NULL,
+    "update_date" date NOT NULL,
+    "reference" DECIMAL NOT NULL,
+    "limit_up" DECIMAL NOT NULL,
+    "limit_down" DECIMAL NOT NULL,
+    "created_at" timestamptz NOT NULL,
+    PRIMARY KEY ("code", "kind", "update_date")
+);
+COMMIT;
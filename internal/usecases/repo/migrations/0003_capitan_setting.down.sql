@@ -0,0 +1,13 @@
+BEGIN;
+INSERT INTO system_setting ("key", "setting", "updated_at")
+SELECT CASE "key"
+        WHEN 'backup_schedule' THEN 1001
+        WHEN 'backup_scheduled' THEN 1002
+        WHEN 'backup_targets' THEN 1003
+    END,
+    "setting", "updated_at"
+FROM capitan_setting
+WHERE "key" IN ('backup_schedule', 'backup_scheduled', 'backup_targets')
+ON CONFLICT ("key") DO NOTHING;
+DROP TABLE IF EXISTS capitan_setting;
+COMMIT;
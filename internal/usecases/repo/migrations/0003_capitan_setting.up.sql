@@ -0,0 +1,17 @@
+BEGIN;
+CREATE TABLE capitan_setting(
+    "key" varchar PRIMARY KEY,
+    "setting" bytea NOT NULL,
+    "updated_at" timestamptz NOT NULL
+);
+INSERT INTO capitan_setting ("key", "setting", "updated_at")
+SELECT CASE "key"
+        WHEN 1001 THEN 'backup_schedule'
+        WHEN 1002 THEN 'backup_scheduled'
+        WHEN 1003 THEN 'backup_targets'
+    END,
+    "setting", "updated_at"
+FROM system_setting
+WHERE "key" IN (1001, 1002, 1003);
+DELETE FROM system_setting WHERE "key" IN (1001, 1002, 1003);
+COMMIT;
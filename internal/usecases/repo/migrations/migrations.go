@@ -0,0 +1,10 @@
+// Package migrations embeds the schema owned by capitan, applied after the schema from panther.
+package migrations
+
+import "embed"
+
+// Table keeps the version apart from the panther schema_migrations.
+const Table = "capitan_schema_migrations"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: backup_postgres.go
+//
+// Generated by this command:
+//
+//	mockgen -source=backup_postgres.go -destination=./mocks/mocks_backup_postgres_test.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBackupRepo is a mock of BackupRepo interface.
+type MockBackupRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackupRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockBackupRepoMockRecorder is the mock recorder for MockBackupRepo.
+type MockBackupRepoMockRecorder struct {
+	mock *MockBackupRepo
+}
+
+// NewMockBackupRepo creates a new mock instance.
+func NewMockBackupRepo(ctrl *gomock.Controller) *MockBackupRepo {
+	mock := &MockBackupRepo{ctrl: ctrl}
+	mock.recorder = &MockBackupRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackupRepo) EXPECT() *MockBackupRepoMockRecorder {
+	return m.recorder
+}
+
+// CountRows mocks base method.
+func (m *MockBackupRepo) CountRows(ctx context.Context) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRows", ctx)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRows indicates an expected call of CountRows.
+func (mr *MockBackupRepoMockRecorder) CountRows(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRows", reflect.TypeOf((*MockBackupRepo)(nil).CountRows), ctx)
+}
+
+// CreateDatabase mocks base method.
+func (m *MockBackupRepo) CreateDatabase(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDatabase", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDatabase indicates an expected call of CreateDatabase.
+func (mr *MockBackupRepoMockRecorder) CreateDatabase(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDatabase", reflect.TypeOf((*MockBackupRepo)(nil).CreateDatabase), ctx, name)
+}
+
+// DropDatabase mocks base method.
+func (m *MockBackupRepo) DropDatabase(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropDatabase", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropDatabase indicates an expected call of DropDatabase.
+func (mr *MockBackupRepoMockRecorder) DropDatabase(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropDatabase", reflect.TypeOf((*MockBackupRepo)(nil).DropDatabase), ctx, name)
+}
+
+// SelectSchemaVersion mocks base method.
+func (m *MockBackupRepo) SelectSchemaVersion(ctx context.Context) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectSchemaVersion", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SelectSchemaVersion indicates an expected call of SelectSchemaVersion.
+func (mr *MockBackupRepoMockRecorder) SelectSchemaVersion(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectSchemaVersion", reflect.TypeOf((*MockBackupRepo)(nil).SelectSchemaVersion), ctx)
+}
@@ -12,7 +12,9 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	entity "github.com/chindada/capitan/internal/usecases/entity"
 	pb "github.com/chindada/panther/golang/pb"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,44 +43,133 @@ func (m *MockBasicRepo) EXPECT() *MockBasicRepoMockRecorder {
 	return m.recorder
 }
 
-// InsertFutureDetail mocks base method.
-func (m *MockBasicRepo) InsertFutureDetail(ctx context.Context, t []*pb.FutureDetail) error {
+// ImportFutureDetail mocks base method.
+func (m *MockBasicRepo) ImportFutureDetail(ctx context.Context, t []*entity.FutureRow, removed []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertFutureDetail", ctx, t)
+	ret := m.ctrl.Call(m, "ImportFutureDetail", ctx, t, removed)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// InsertFutureDetail indicates an expected call of InsertFutureDetail.
-func (mr *MockBasicRepoMockRecorder) InsertFutureDetail(ctx, t any) *gomock.Call {
+// ImportFutureDetail indicates an expected call of ImportFutureDetail.
+func (mr *MockBasicRepoMockRecorder) ImportFutureDetail(ctx, t, removed any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertFutureDetail", reflect.TypeOf((*MockBasicRepo)(nil).InsertFutureDetail), ctx, t)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportFutureDetail", reflect.TypeOf((*MockBasicRepo)(nil).ImportFutureDetail), ctx, t, removed)
 }
 
-// InsertOptionDetail mocks base method.
-func (m *MockBasicRepo) InsertOptionDetail(ctx context.Context, t []*pb.OptionDetail) error {
+// ImportOptionDetail mocks base method.
+func (m *MockBasicRepo) ImportOptionDetail(ctx context.Context, t []*entity.OptionRow, removed []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertOptionDetail", ctx, t)
+	ret := m.ctrl.Call(m, "ImportOptionDetail", ctx, t, removed)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// InsertOptionDetail indicates an expected call of InsertOptionDetail.
-func (mr *MockBasicRepoMockRecorder) InsertOptionDetail(ctx, t any) *gomock.Call {
+// ImportOptionDetail indicates an expected call of ImportOptionDetail.
+func (mr *MockBasicRepoMockRecorder) ImportOptionDetail(ctx, t, removed any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOptionDetail", reflect.TypeOf((*MockBasicRepo)(nil).InsertOptionDetail), ctx, t)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportOptionDetail", reflect.TypeOf((*MockBasicRepo)(nil).ImportOptionDetail), ctx, t, removed)
 }
 
-// InsertStockDetail mocks base method.
-func (m *MockBasicRepo) InsertStockDetail(ctx context.Context, t []*pb.StockDetail) error {
+// ImportStockDetail mocks base method.
+func (m *MockBasicRepo) ImportStockDetail(ctx context.Context, t []*entity.StockRow, removed []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertStockDetail", ctx, t)
+	ret := m.ctrl.Call(m, "ImportStockDetail", ctx, t, removed)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// InsertStockDetail indicates an expected call of InsertStockDetail.
-func (mr *MockBasicRepoMockRecorder) InsertStockDetail(ctx, t any) *gomock.Call {
+// ImportStockDetail indicates an expected call of ImportStockDetail.
+func (mr *MockBasicRepoMockRecorder) ImportStockDetail(ctx, t, removed any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertStockDetail", reflect.TypeOf((*MockBasicRepo)(nil).InsertStockDetail), ctx, t)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportStockDetail", reflect.TypeOf((*MockBasicRepo)(nil).ImportStockDetail), ctx, t, removed)
+}
+
+// MergeStockDetail mocks base method.
+func (m *MockBasicRepo) MergeStockDetail(ctx context.Context, t []*entity.StockRow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeStockDetail", ctx, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeStockDetail indicates an expected call of MergeStockDetail.
+func (mr *MockBasicRepoMockRecorder) MergeStockDetail(ctx, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeStockDetail", reflect.TypeOf((*MockBasicRepo)(nil).MergeStockDetail), ctx, t)
+}
+
+// SelectActiveStockDetail mocks base method.
+func (m *MockBasicRepo) SelectActiveStockDetail(ctx context.Context) ([]*pb.StockDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectActiveStockDetail", ctx)
+	ret0, _ := ret[0].([]*pb.StockDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectActiveStockDetail indicates an expected call of SelectActiveStockDetail.
+func (mr *MockBasicRepoMockRecorder) SelectActiveStockDetail(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectActiveStockDetail", reflect.TypeOf((*MockBasicRepo)(nil).SelectActiveStockDetail), ctx)
+}
+
+// SelectFutureDetail mocks base method.
+func (m *MockBasicRepo) SelectFutureDetail(ctx context.Context, filter *entity.ContractFilter) ([]*pb.FutureDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectFutureDetail", ctx, filter)
+	ret0, _ := ret[0].([]*pb.FutureDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectFutureDetail indicates an expected call of SelectFutureDetail.
+func (mr *MockBasicRepoMockRecorder) SelectFutureDetail(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectFutureDetail", reflect.TypeOf((*MockBasicRepo)(nil).SelectFutureDetail), ctx, filter)
+}
+
+// SelectInstrumentCodes mocks base method.
+func (m *MockBasicRepo) SelectInstrumentCodes(ctx context.Context, kind entity.InstrumentKind) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectInstrumentCodes", ctx, kind)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectInstrumentCodes indicates an expected call of SelectInstrumentCodes.
+func (mr *MockBasicRepoMockRecorder) SelectInstrumentCodes(ctx, kind any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectInstrumentCodes", reflect.TypeOf((*MockBasicRepo)(nil).SelectInstrumentCodes), ctx, kind)
+}
+
+// SelectOptionDetail mocks base method.
+func (m *MockBasicRepo) SelectOptionDetail(ctx context.Context, filter *entity.ContractFilter) ([]*pb.OptionDetail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectOptionDetail", ctx, filter)
+	ret0, _ := ret[0].([]*pb.OptionDetail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectOptionDetail indicates an expected call of SelectOptionDetail.
+func (mr *MockBasicRepoMockRecorder) SelectOptionDetail(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectOptionDetail", reflect.TypeOf((*MockBasicRepo)(nil).SelectOptionDetail), ctx, filter)
+}
+
+// SelectPriceHistory mocks base method.
+func (m *MockBasicRepo) SelectPriceHistory(ctx context.Context, kind entity.InstrumentKind, code string, from, to time.Time) ([]*entity.PriceHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectPriceHistory", ctx, kind, code, from, to)
+	ret0, _ := ret[0].([]*entity.PriceHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectPriceHistory indicates an expected call of SelectPriceHistory.
+func (mr *MockBasicRepoMockRecorder) SelectPriceHistory(ctx, kind, code, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectPriceHistory", reflect.TypeOf((*MockBasicRepo)(nil).SelectPriceHistory), ctx, kind, code, from, to)
 }
@@ -13,6 +13,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	entity "github.com/chindada/capitan/internal/usecases/entity"
 	pb "github.com/chindada/panther/golang/pb"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,6 +42,20 @@ func (m *MockSystemRepo) EXPECT() *MockSystemRepoMockRecorder {
 	return m.recorder
 }
 
+// ImportLocalSettings mocks base method.
+func (m *MockSystemRepo) ImportLocalSettings(ctx context.Context, settings map[entity.LocalSettingKey][]byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportLocalSettings", ctx, settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportLocalSettings indicates an expected call of ImportLocalSettings.
+func (mr *MockSystemRepoMockRecorder) ImportLocalSettings(ctx, settings any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportLocalSettings", reflect.TypeOf((*MockSystemRepo)(nil).ImportLocalSettings), ctx, settings)
+}
+
 // InsertLoginEvent mocks base method.
 func (m *MockSystemRepo) InsertLoginEvent(ctx context.Context, events []*pb.LoginEvent) error {
 	m.ctrl.T.Helper()
@@ -55,18 +70,34 @@ func (mr *MockSystemRepoMockRecorder) InsertLoginEvent(ctx, events any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertLoginEvent", reflect.TypeOf((*MockSystemRepo)(nil).InsertLoginEvent), ctx, events)
 }
 
-// InsertSetting mocks base method.
-func (m *MockSystemRepo) InsertSetting(ctx context.Context, s *pb.SystemSetting) error {
+// SelectAllLocalSetting mocks base method.
+func (m *MockSystemRepo) SelectAllLocalSetting(ctx context.Context) (map[entity.LocalSettingKey][]byte, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertSetting", ctx, s)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "SelectAllLocalSetting", ctx)
+	ret0, _ := ret[0].(map[entity.LocalSettingKey][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// InsertSetting indicates an expected call of InsertSetting.
-func (mr *MockSystemRepoMockRecorder) InsertSetting(ctx, s any) *gomock.Call {
+// SelectAllLocalSetting indicates an expected call of SelectAllLocalSetting.
+func (mr *MockSystemRepoMockRecorder) SelectAllLocalSetting(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSetting", reflect.TypeOf((*MockSystemRepo)(nil).InsertSetting), ctx, s)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAllLocalSetting", reflect.TypeOf((*MockSystemRepo)(nil).SelectAllLocalSetting), ctx)
+}
+
+// SelectLocalSetting mocks base method.
+func (m *MockSystemRepo) SelectLocalSetting(ctx context.Context, key entity.LocalSettingKey) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectLocalSetting", ctx, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectLocalSetting indicates an expected call of SelectLocalSetting.
+func (mr *MockSystemRepoMockRecorder) SelectLocalSetting(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectLocalSetting", reflect.TypeOf((*MockSystemRepo)(nil).SelectLocalSetting), ctx, key)
 }
 
 // SelectLoginEvent mocks base method.
@@ -84,6 +115,21 @@ func (mr *MockSystemRepoMockRecorder) SelectLoginEvent(ctx, limit any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectLoginEvent", reflect.TypeOf((*MockSystemRepo)(nil).SelectLoginEvent), ctx, limit)
 }
 
+// SelectRawSetting mocks base method.
+func (m *MockSystemRepo) SelectRawSetting(ctx context.Context, key pb.SettingKey) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectRawSetting", ctx, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectRawSetting indicates an expected call of SelectRawSetting.
+func (mr *MockSystemRepoMockRecorder) SelectRawSetting(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectRawSetting", reflect.TypeOf((*MockSystemRepo)(nil).SelectRawSetting), ctx, key)
+}
+
 // SelectSetting mocks base method.
 func (m *MockSystemRepo) SelectSetting(ctx context.Context, key pb.SettingKey) (*pb.SystemSetting, error) {
 	m.ctrl.T.Helper()
@@ -99,16 +145,44 @@ func (mr *MockSystemRepoMockRecorder) SelectSetting(ctx, key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectSetting", reflect.TypeOf((*MockSystemRepo)(nil).SelectSetting), ctx, key)
 }
 
-// UpdateSetting mocks base method.
-func (m *MockSystemRepo) UpdateSetting(ctx context.Context, s *pb.SystemSetting) error {
+// UpsertLocalSetting mocks base method.
+func (m *MockSystemRepo) UpsertLocalSetting(ctx context.Context, key entity.LocalSettingKey, data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertLocalSetting", ctx, key, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertLocalSetting indicates an expected call of UpsertLocalSetting.
+func (mr *MockSystemRepoMockRecorder) UpsertLocalSetting(ctx, key, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLocalSetting", reflect.TypeOf((*MockSystemRepo)(nil).UpsertLocalSetting), ctx, key, data)
+}
+
+// UpsertRawSetting mocks base method.
+func (m *MockSystemRepo) UpsertRawSetting(ctx context.Context, key pb.SettingKey, data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertRawSetting", ctx, key, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertRawSetting indicates an expected call of UpsertRawSetting.
+func (mr *MockSystemRepoMockRecorder) UpsertRawSetting(ctx, key, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertRawSetting", reflect.TypeOf((*MockSystemRepo)(nil).UpsertRawSetting), ctx, key, data)
+}
+
+// UpsertSetting mocks base method.
+func (m *MockSystemRepo) UpsertSetting(ctx context.Context, s *pb.SystemSetting) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateSetting", ctx, s)
+	ret := m.ctrl.Call(m, "UpsertSetting", ctx, s)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateSetting indicates an expected call of UpdateSetting.
-func (mr *MockSystemRepoMockRecorder) UpdateSetting(ctx, s any) *gomock.Call {
+// UpsertSetting indicates an expected call of UpsertSetting.
+func (mr *MockSystemRepoMockRecorder) UpsertSetting(ctx, s any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSetting", reflect.TypeOf((*MockSystemRepo)(nil).UpdateSetting), ctx, s)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertSetting", reflect.TypeOf((*MockSystemRepo)(nil).UpsertSetting), ctx, s)
 }
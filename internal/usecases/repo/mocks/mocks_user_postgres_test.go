@@ -69,6 +69,20 @@ func (mr *MockUserRepoMockRecorder) DeleteUser(ctx, username any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockUserRepo)(nil).DeleteUser), ctx, username)
 }
 
+// ImportUsers mocks base method.
+func (m *MockUserRepo) ImportUsers(ctx context.Context, inserted, updated []*pb.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportUsers", ctx, inserted, updated)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportUsers indicates an expected call of ImportUsers.
+func (mr *MockUserRepoMockRecorder) ImportUsers(ctx, inserted, updated any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportUsers", reflect.TypeOf((*MockUserRepo)(nil).ImportUsers), ctx, inserted, updated)
+}
+
 // InsertUser mocks base method.
 func (m *MockUserRepo) InsertUser(ctx context.Context, t *pb.User) error {
 	m.ctrl.T.Helper()
@@ -98,6 +112,21 @@ func (mr *MockUserRepoMockRecorder) SelectAllUser(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAllUser", reflect.TypeOf((*MockUserRepo)(nil).SelectAllUser), ctx)
 }
 
+// SelectAllUserCredential mocks base method.
+func (m *MockUserRepo) SelectAllUserCredential(ctx context.Context) ([]*pb.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectAllUserCredential", ctx)
+	ret0, _ := ret[0].([]*pb.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectAllUserCredential indicates an expected call of SelectAllUserCredential.
+func (mr *MockUserRepoMockRecorder) SelectAllUserCredential(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectAllUserCredential", reflect.TypeOf((*MockUserRepo)(nil).SelectAllUserCredential), ctx)
+}
+
 // SelectTotpByID mocks base method.
 func (m *MockUserRepo) SelectTotpByID(ctx context.Context, id int64) (*pb.Totp, error) {
 	m.ctrl.T.Helper()
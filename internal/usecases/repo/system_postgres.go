@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/chindada/capitan/internal/usecases/entity"
 	"github.com/chindada/panther/golang/pb"
 	"github.com/chindada/panther/pkg/client"
 	"github.com/jackc/pgx/v5"
@@ -17,8 +18,15 @@ import (
 
 type SystemRepo interface {
 	SelectSetting(ctx context.Context, key pb.SettingKey) (*pb.SystemSetting, error)
-	InsertSetting(ctx context.Context, s *pb.SystemSetting) error
-	UpdateSetting(ctx context.Context, s *pb.SystemSetting) error
+	UpsertSetting(ctx context.Context, s *pb.SystemSetting) error
+
+	SelectRawSetting(ctx context.Context, key pb.SettingKey) ([]byte, error)
+	UpsertRawSetting(ctx context.Context, key pb.SettingKey, data []byte) error
+
+	SelectLocalSetting(ctx context.Context, key entity.LocalSettingKey) ([]byte, error)
+	UpsertLocalSetting(ctx context.Context, key entity.LocalSettingKey, data []byte) error
+	SelectAllLocalSetting(ctx context.Context) (map[entity.LocalSettingKey][]byte, error)
+	ImportLocalSettings(ctx context.Context, settings map[entity.LocalSettingKey][]byte) error
 
 	InsertLoginEvent(ctx context.Context, events []*pb.LoginEvent) error
 	SelectLoginEvent(ctx context.Context, limit int64) ([]*pb.LoginEvent, error)
@@ -33,6 +41,8 @@ func NewSystemRepo(pg client.PGClient) SystemRepo {
 }
 
 func (r *system) SelectSetting(ctx context.Context, key pb.SettingKey) (*pb.SystemSetting, error) {
+	ctx, span := startSpan(ctx, "SystemRepo.SelectSetting")
+	defer span.End()
 	sql, arg, err := r.Builder().
 		Select("setting").
 		From(tableNameSystemSetting).
@@ -56,20 +66,52 @@ func (r *system) SelectSetting(ctx context.Context, key pb.SettingKey) (*pb.Syst
 	return &s, nil
 }
 
-func (r *system) InsertSetting(ctx context.Context, s *pb.SystemSetting) error {
+// UpsertSetting stores s by its key, replaced if exists.
+func (r *system) UpsertSetting(ctx context.Context, s *pb.SystemSetting) error {
+	ctx, span := startSpan(ctx, "SystemRepo.UpsertSetting")
+	defer span.End()
 	data, err := proto.Marshal(s)
 	if err != nil {
 		return err
 	}
+	return r.UpsertRawSetting(ctx, s.GetKey(), data)
+}
+
+// SelectRawSetting returns the content of key as is, nil if not found.
+func (r *system) SelectRawSetting(ctx context.Context, key pb.SettingKey) ([]byte, error) {
+	ctx, span := startSpan(ctx, "SystemRepo.SelectRawSetting")
+	defer span.End()
+	sql, arg, err := r.Builder().
+		Select("setting").
+		From(tableNameSystemSetting).
+		Where(squirrel.Eq{"key": key}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var content []byte
+	if err = r.Pool().QueryRow(ctx, sql, arg...).Scan(&content); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return content, nil
+}
+
+// UpsertRawSetting stores data of key as is, the keys of capitan go to UpsertLocalSetting.
+func (r *system) UpsertRawSetting(ctx context.Context, key pb.SettingKey, data []byte) error {
+	ctx, span := startSpan(ctx, "SystemRepo.UpsertRawSetting")
+	defer span.End()
 	sql, args, err := r.Builder().
 		Insert(tableNameSystemSetting).
 		Columns("key, setting, updated_at").
-		Values(s.GetKey(), data, time.Now()).
+		Values(key, data, time.Now()).
+		Suffix("ON CONFLICT (key) DO UPDATE SET setting = EXCLUDED.setting, updated_at = EXCLUDED.updated_at").
 		ToSql()
 	if err != nil {
 		return err
 	}
-
 	tx, err := r.Pool().Begin(ctx)
 	if err != nil {
 		return err
@@ -82,33 +124,95 @@ func (r *system) InsertSetting(ctx context.Context, s *pb.SystemSetting) error {
 	return tx.Commit(ctx)
 }
 
-func (r *system) UpdateSetting(ctx context.Context, s *pb.SystemSetting) error {
-	data, err := proto.Marshal(s)
+// SelectLocalSetting returns the content of key as is, nil if not found.
+func (r *system) SelectLocalSetting(ctx context.Context, key entity.LocalSettingKey) ([]byte, error) {
+	ctx, span := startSpan(ctx, "SystemRepo.SelectLocalSetting")
+	defer span.End()
+	sql, arg, err := r.Builder().
+		Select("setting").
+		From(tableNameCapitanSetting).
+		Where(squirrel.Eq{"key": string(key)}).
+		ToSql()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	sql, args, err := r.Builder().
-		Update(tableNameSystemSetting).
-		Set("setting", data).
-		Set("updated_at", time.Now()).
-		Where(squirrel.Eq{"key": s.GetKey()}).
+	var content []byte
+	if err = r.Pool().QueryRow(ctx, sql, arg...).Scan(&content); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return content, nil
+}
+
+// UpsertLocalSetting stores data of key, replaced if exists.
+func (r *system) UpsertLocalSetting(ctx context.Context, key entity.LocalSettingKey, data []byte) error {
+	ctx, span := startSpan(ctx, "SystemRepo.UpsertLocalSetting")
+	defer span.End()
+	return r.ImportLocalSettings(ctx, map[entity.LocalSettingKey][]byte{key: data})
+}
+
+// SelectAllLocalSetting returns the content of all keys as is.
+func (r *system) SelectAllLocalSetting(ctx context.Context) (map[entity.LocalSettingKey][]byte, error) {
+	ctx, span := startSpan(ctx, "SystemRepo.SelectAllLocalSetting")
+	defer span.End()
+	sql, arg, err := r.Builder().
+		Select("key, setting").
+		From(tableNameCapitanSetting).
 		ToSql()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	rows, err := r.Pool().Query(ctx, sql, arg...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[entity.LocalSettingKey][]byte)
+	for rows.Next() {
+		var key string
+		var content []byte
+		if err = rows.Scan(&key, &content); err != nil {
+			return nil, err
+		}
+		result[entity.LocalSettingKey(key)] = content
+	}
+	return result, rows.Err()
+}
+
+// ImportLocalSettings upserts all settings in one transaction.
+func (r *system) ImportLocalSettings(ctx context.Context, settings map[entity.LocalSettingKey][]byte) error {
+	ctx, span := startSpan(ctx, "SystemRepo.ImportLocalSettings")
+	defer span.End()
 	tx, err := r.Pool().Begin(ctx)
 	if err != nil {
 		return err
 	}
 	defer r.Rollback(ctx, tx)
 
-	if _, err = tx.Exec(ctx, sql, args...); err != nil {
-		return err
+	now := time.Now()
+	for key, data := range settings {
+		sql, args, bErr := r.Builder().
+			Insert(tableNameCapitanSetting).
+			Columns("key, setting, updated_at").
+			Values(string(key), data, now).
+			Suffix("ON CONFLICT (key) DO UPDATE SET setting = EXCLUDED.setting, updated_at = EXCLUDED.updated_at").
+			ToSql()
+		if bErr != nil {
+			return bErr
+		}
+		if _, err = tx.Exec(ctx, sql, args...); err != nil {
+			return err
+		}
 	}
 	return tx.Commit(ctx)
 }
 
 func (r *system) InsertLoginEvent(ctx context.Context, events []*pb.LoginEvent) error {
+	ctx, span := startSpan(ctx, "SystemRepo.InsertLoginEvent")
+	defer span.End()
 	builder := r.Builder().
 		Insert(tableNameSystemEventLogin).
 		Columns("account_id, ip, resp_code, created_at")
@@ -140,6 +244,8 @@ func (r *system) InsertLoginEvent(ctx context.Context, events []*pb.LoginEvent)
 }
 
 func (r *system) SelectLoginEvent(ctx context.Context, limit int64) ([]*pb.LoginEvent, error) {
+	ctx, span := startSpan(ctx, "SystemRepo.SelectLoginEvent")
+	defer span.End()
 	sql, args, err := r.Builder().
 		Select(`
 			system_event_login.id, COALESCE(system_event_login.account_id,0), system_event_login.ip,
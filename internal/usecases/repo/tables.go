@@ -5,8 +5,14 @@ const (
 	tableNameBasicFuture string = "basic_future"
 	tableNameBasicOption string = "basic_option"
 
+	tableNameBasicPriceHistory string = "basic_price_history"
+
 	tableNameSystemAccount    string = "system_account"
 	tableNameSystemSetting    string = "system_setting"
 	tableNameSystemEventLogin string = "system_event_login"
 	tableNameSystemTotp       string = "system_totp"
+
+	tableNameCapitanSetting string = "capitan_setting"
+
+	tableNameSchemaMigrations string = "schema_migrations"
 )
@@ -22,6 +22,9 @@ type UserRepo interface {
 	SelectUserIDByUsername(ctx context.Context, username string) (int64, error)
 	DeleteUser(ctx context.Context, username string) error
 
+	SelectAllUserCredential(ctx context.Context) ([]*pb.User, error)
+	ImportUsers(ctx context.Context, inserted, updated []*pb.User) error
+
 	ActivateUserTotp(ctx context.Context, t *pb.User, totp *pb.Totp) error
 	SelectTotpByID(ctx context.Context, id int64) (*pb.Totp, error)
 }
@@ -69,6 +72,8 @@ func (r *user) deleteTotpByID(ctx context.Context, tx pgx.Tx, id int64) error {
 }
 
 func (r *user) SelectTotpByID(ctx context.Context, id int64) (*pb.Totp, error) {
+	ctx, span := startSpan(ctx, "UserRepo.SelectTotpByID")
+	defer span.End()
 	sql, arg, err := r.Builder().
 		Select("secret, qr_code").
 		From(tableNameSystemTotp).
@@ -90,6 +95,8 @@ func (r *user) SelectTotpByID(ctx context.Context, id int64) (*pb.Totp, error) {
 }
 
 func (r *user) InsertUser(ctx context.Context, t *pb.User) error {
+	ctx, span := startSpan(ctx, "UserRepo.InsertUser")
+	defer span.End()
 	sql, args, err := r.Builder().Insert(tableNameSystemAccount).
 		Columns("username, password, email, role, created_at, updated_at").
 		Values(
@@ -115,6 +122,8 @@ func (r *user) InsertUser(ctx context.Context, t *pb.User) error {
 }
 
 func (r *user) UpdateUser(ctx context.Context, t *pb.User) error {
+	ctx, span := startSpan(ctx, "UserRepo.UpdateUser")
+	defer span.End()
 	sql, args, err := r.Builder().
 		Update(tableNameSystemAccount).
 		Set("email", t.GetBasic().GetEmail()).
@@ -139,6 +148,8 @@ func (r *user) UpdateUser(ctx context.Context, t *pb.User) error {
 }
 
 func (r *user) UpdateUserPassword(ctx context.Context, t *pb.User) error {
+	ctx, span := startSpan(ctx, "UserRepo.UpdateUserPassword")
+	defer span.End()
 	sql, args, err := r.Builder().Update(tableNameSystemAccount).
 		Set("password", t.GetBasic().GetPassword()).
 		Set("updated_at", time.Now()).
@@ -161,6 +172,8 @@ func (r *user) UpdateUserPassword(ctx context.Context, t *pb.User) error {
 }
 
 func (r *user) ActivateUserTotp(ctx context.Context, t *pb.User, totp *pb.Totp) error {
+	ctx, span := startSpan(ctx, "UserRepo.ActivateUserTotp")
+	defer span.End()
 	tx, err := r.Pool().Begin(ctx)
 	if err != nil {
 		return err
@@ -191,6 +204,8 @@ func (r *user) ActivateUserTotp(ctx context.Context, t *pb.User, totp *pb.Totp)
 }
 
 func (r *user) SelectUserByUsername(ctx context.Context, username string) (*pb.User, error) {
+	ctx, span := startSpan(ctx, "UserRepo.SelectUserByUsername")
+	defer span.End()
 	sql, arg, err := r.Builder().
 		Select("id, username, password, email, role, enable_totp, COALESCE(totp_id,0)").
 		From(tableNameSystemAccount).
@@ -218,6 +233,8 @@ func (r *user) SelectUserByUsername(ctx context.Context, username string) (*pb.U
 }
 
 func (r *user) SelectUserByID(ctx context.Context, id int64) (*pb.User, error) {
+	ctx, span := startSpan(ctx, "UserRepo.SelectUserByID")
+	defer span.End()
 	sql, arg, err := r.Builder().
 		Select("id, username, password, email, role, enable_totp, COALESCE(totp_id,0)").
 		From(tableNameSystemAccount).
@@ -245,6 +262,8 @@ func (r *user) SelectUserByID(ctx context.Context, id int64) (*pb.User, error) {
 }
 
 func (r *user) SelectAllUser(ctx context.Context) (*pb.UserList, error) {
+	ctx, span := startSpan(ctx, "UserRepo.SelectAllUser")
+	defer span.End()
 	sql, arg, err := r.Builder().
 		Select("id, username, email, role, enable_totp, COALESCE(totp_id,0)").
 		From(tableNameSystemAccount).
@@ -278,6 +297,8 @@ func (r *user) SelectAllUser(ctx context.Context) (*pb.UserList, error) {
 }
 
 func (r *user) DeleteUser(ctx context.Context, username string) error {
+	ctx, span := startSpan(ctx, "UserRepo.DeleteUser")
+	defer span.End()
 	user, err := r.SelectUserByUsername(ctx, username)
 	if err != nil {
 		return err
@@ -309,6 +330,8 @@ func (r *user) DeleteUser(ctx context.Context, username string) error {
 }
 
 func (r *user) SelectUserIDByUsername(ctx context.Context, username string) (int64, error) {
+	ctx, span := startSpan(ctx, "UserRepo.SelectUserIDByUsername")
+	defer span.End()
 	sql, arg, err := r.Builder().
 		Select("id").
 		From(tableNameSystemAccount).
@@ -328,3 +351,84 @@ func (r *user) SelectUserIDByUsername(ctx context.Context, username string) (int
 	}
 	return id, nil
 }
+
+// SelectAllUserCredential returns the basic of all users with the password hash, sorted by username.
+func (r *user) SelectAllUserCredential(ctx context.Context) ([]*pb.User, error) {
+	ctx, span := startSpan(ctx, "UserRepo.SelectAllUserCredential")
+	defer span.End()
+	sql, arg, err := r.Builder().
+		Select("id, username, password, email, role").
+		From(tableNameSystemAccount).
+		OrderBy("username ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.Pool().Query(ctx, sql, arg...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []*pb.User{}
+	for rows.Next() {
+		e := pb.User{
+			Basic: &pb.BasicUser{},
+		}
+		if err = rows.Scan(
+			&e.Id, &e.Basic.Username, &e.Basic.Password, &e.Basic.Email, &e.Basic.Role,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, &e)
+	}
+	return result, rows.Err()
+}
+
+// ImportUsers inserts and updates the users in one transaction, the password is the hash as is.
+// Updated users are matched by username, the totp is kept.
+func (r *user) ImportUsers(ctx context.Context, inserted, updated []*pb.User) error {
+	ctx, span := startSpan(ctx, "UserRepo.ImportUsers")
+	defer span.End()
+	tx, err := r.Pool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer r.Rollback(ctx, tx)
+
+	now := time.Now()
+	for _, t := range inserted {
+		sql, args, bErr := r.Builder().Insert(tableNameSystemAccount).
+			Columns("username, password, email, role, created_at, updated_at").
+			Values(
+				t.GetBasic().GetUsername(), t.GetBasic().GetPassword(),
+				t.GetBasic().GetEmail(), t.GetBasic().GetRole(),
+				now, now,
+			).
+			ToSql()
+		if bErr != nil {
+			return bErr
+		}
+		if _, err = tx.Exec(ctx, sql, args...); err != nil {
+			return err
+		}
+	}
+	for _, t := range updated {
+		sql, args, bErr := r.Builder().
+			Update(tableNameSystemAccount).
+			Set("password", t.GetBasic().GetPassword()).
+			Set("email", t.GetBasic().GetEmail()).
+			Set("role", t.GetBasic().GetRole()).
+			Set("updated_at", now).
+			Where("username = ?", t.GetBasic().GetUsername()).
+			ToSql()
+		if bErr != nil {
+			return bErr
+		}
+		if _, err = tx.Exec(ctx, sql, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,17 @@
+package usecases
+
+import "github.com/chindada/capitan/internal/config"
+
+// Topics published on eventbus.Bus.
+const (
+	// TopicInstrumentDiff publishes *entity.InstrumentDiff after each refresh of a kind.
+	TopicInstrumentDiff = "instrument_diff"
+	// TopicSettingChanged publishes the pb.SettingKey after the setting is written.
+	TopicSettingChanged = "setting_changed"
+	// TopicLocalSettingChanged publishes the entity.LocalSettingKey after the setting of capitan is written.
+	TopicLocalSettingChanged = "local_setting_changed"
+	// TopicConfigReloaded publishes *entity.ConfigReload after the config reloaded with any key applied.
+	TopicConfigReloaded = "config_reloaded"
+	// TopicUpstreamChanged publishes the bool available after the panther grpc connected or lost, by config.
+	TopicUpstreamChanged = config.TopicUpstreamChanged
+)
@@ -0,0 +1,6 @@
+package usecases
+
+import "go.opentelemetry.io/otel"
+
+// tracer starts the root spans of the jobs in background, the requests are traced by the router.
+var tracer = otel.Tracer("github.com/chindada/capitan/internal/usecases")
@@ -0,0 +1,1036 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chindada/capitan/internal/config"
+	"github.com/chindada/capitan/internal/usecases/entity"
+	"github.com/chindada/capitan/internal/usecases/modules/crypt"
+	"github.com/chindada/capitan/internal/usecases/modules/manifest"
+	"github.com/chindada/capitan/internal/usecases/repo"
+	"github.com/chindada/capitan/internal/version"
+	"github.com/chindada/leopard/pkg/eventbus"
+	"github.com/chindada/leopard/pkg/log"
+	"github.com/chindada/panther/pkg/client"
+	"github.com/chindada/panther/pkg/launcher"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel/codes"
+)
+
+//go:generate mockgen -source=usecase_backup.go -destination=./mocks/mocks_usecase_backup_test.go -package=mocks
+
+// Setting keys of capitan_setting.
+const (
+	settingKeyBackupSchedule  entity.LocalSettingKey = "backup_schedule"
+	settingKeyBackupScheduled entity.LocalSettingKey = "backup_scheduled"
+	settingKeyBackupTargets   entity.LocalSettingKey = "backup_targets"
+)
+
+var defaultBackupSchedule = entity.BackupSchedule{
+	Enabled:   true,
+	Spec:      "0 3 * * *",
+	KeepCount: 7,
+	KeepDays:  30,
+}
+
+const (
+	// maxKeptJobs is the number of finished jobs kept in the job file.
+	maxKeptJobs = 100
+	// restoreDrainTimeout is the max wait of in-flight requests before restore.
+	restoreDrainTimeout = 30 * time.Second
+	// scratchDatabasePrefix is the name prefix of the dry-run restore databases.
+	scratchDatabasePrefix = "capitan_verify"
+	// launcherMetaFile is written by the launcher and read before the manifest, never encrypted.
+	launcherMetaFile = ".meta"
+)
+
+// Maintainer takes capitan offline around the in-process restore, implemented by the app.
+// Drain stops the traffic and the database bound use cases, then closes the pool.
+// Resume reopens the database, rebuilds the pool and the use cases, runs rebind, then serves again.
+type Maintainer interface {
+	Drain(ctx context.Context) error
+	Resume(rebind func()) error
+}
+
+type Backup interface {
+	GetScheduleStatus() *entity.BackupScheduleStatus
+	UpdateSchedule(ctx context.Context, s *entity.BackupSchedule) error
+
+	CreateBackupJob() (*entity.Job, error)
+	CreateRestoreJob(name string) (*entity.Job, error)
+	OpenArchive(name, passphrase string) (*BackupArchive, error)
+	UploadBackup(fileName string, src io.Reader, passphrase string) (*entity.Job, error)
+	CreateUploadSession(fileName string, size int64) (*entity.UploadSession, error)
+	GetUploadSession(id string) (*entity.UploadSession, error)
+	AppendUploadSession(id string, offset int64, chunk io.Reader, passphrase string) (*entity.UploadSession, error)
+	DeleteUploadSession(id string) error
+	CreateVerifyJob(name string) (*entity.Job, error)
+	GetJob(id string) (*entity.Job, error)
+	ListJobs() []*entity.Job
+	SubscribeJob(ctx context.Context, id string) (<-chan *entity.Job, error)
+
+	GetTargets() []*entity.BackupTargetStatus
+	UpdateTargets(ctx context.Context, targets []*entity.BackupTarget) error
+	ListRemoteBackups(ctx context.Context, target string) ([]*entity.RemoteBackup, error)
+	CreateRemoteRestoreJob(target, name string) (*entity.Job, error)
+
+	Close()
+}
+
+type backupUseCase struct {
+	systemRepo repo.SystemRepo
+	backupRepo repo.BackupRepo
+
+	// ctx is the root context, the running jobs are cancelled on shutdown.
+	ctx    context.Context
+	logger *log.Log
+	bus    *eventbus.Bus
+
+	scheduler *cron.Cron
+	entry     cron.EntryID
+	running   sync.Mutex
+
+	statusLock sync.RWMutex
+	status     entity.BackupScheduleStatus
+
+	jobPath string
+	jobLock sync.Mutex
+	jobs    []*entity.Job
+	jobSubs map[string][]chan *entity.Job
+
+	maintainer Maintainer
+	restoring  sync.Mutex
+	dbLock     sync.RWMutex
+
+	encryptionKey string
+
+	targetLock   sync.RWMutex
+	targets      []*entity.BackupTarget
+	replications map[string]*entity.BackupTargetStatus
+
+	uploadPath    string
+	uploadMaxSize int64
+	uploadLock    sync.Mutex
+	uploads       map[string]*uploadSession
+}
+
+func NewBackup(ctx context.Context, maintainer Maintainer) Backup {
+	cfg := config.Get()
+	uc := &backupUseCase{
+		ctx:        ctx,
+		maintainer: maintainer,
+		systemRepo: repo.NewSystemRepo(cfg.GetPostgresPool()),
+		backupRepo: repo.NewBackupRepo(cfg.GetPostgresPool()),
+		logger:     log.Get(),
+		bus:        eventbus.Get(),
+		scheduler:  cron.New(cron.WithLocation(cfg.Schedule.Location())),
+		jobPath:    cfg.Backup.JobPath,
+		jobSubs:    make(map[string][]chan *entity.Job),
+
+		encryptionKey: cfg.Backup.EncryptionKey,
+		replications:  make(map[string]*entity.BackupTargetStatus),
+		uploadPath:    cfg.Backup.UploadPath,
+		uploadMaxSize: cfg.Backup.UploadMaxSize,
+		uploads:       make(map[string]*uploadSession),
+	}
+	if err := uc.loadJobs(); err != nil {
+		uc.logger.Fatalf("Failed to load backup jobs: %v", err)
+	}
+	if err := uc.loadUploads(); err != nil {
+		uc.logger.Fatalf("Failed to load backup uploads: %v", err)
+	}
+	if err := uc.loadTargets(uc.ctx); err != nil {
+		uc.logger.Fatalf("Failed to load backup targets: %v", err)
+	}
+	schedule, err := uc.loadSchedule(uc.ctx)
+	if err != nil {
+		uc.logger.Fatalf("Failed to load backup schedule: %v", err)
+	}
+	if err = uc.applySchedule(schedule); err != nil {
+		uc.logger.Errorf("Invalid backup schedule %s: %v, use default", schedule.Spec, err)
+		def := defaultBackupSchedule
+		_ = uc.applySchedule(&def)
+	}
+	uc.scheduler.Start()
+	uc.bus.Subscribe(TopicLocalSettingChanged, uc.onSettingChanged)
+	return uc
+}
+
+// Close stops the scheduler and waits the running scheduled backup and restore, no restore starts after.
+func (uc *backupUseCase) Close() {
+	uc.bus.UnSubscribe(TopicLocalSettingChanged, uc.onSettingChanged)
+	<-uc.scheduler.Stop().Done()
+	uc.running.Lock()
+	defer uc.running.Unlock()
+	uc.restoring.Lock()
+}
+
+func (uc *backupUseCase) GetScheduleStatus() *entity.BackupScheduleStatus {
+	uc.statusLock.RLock()
+	status := uc.status
+	schedule := *uc.status.Schedule
+	status.Schedule = &schedule
+	status.Pruned = append([]string{}, uc.status.Pruned...)
+	entry := uc.entry
+	uc.statusLock.RUnlock()
+	if entry != 0 {
+		status.NextRun = uc.scheduler.Entry(entry).Next
+	}
+	return &status
+}
+
+func (uc *backupUseCase) UpdateSchedule(ctx context.Context, s *entity.BackupSchedule) error {
+	if err := validateSchedule(s); err != nil {
+		return ErrBackupScheduleInvalid
+	}
+	if !uc.dbLock.TryRLock() {
+		return ErrRestoreInProgress
+	}
+	defer uc.dbLock.RUnlock()
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err = uc.systemRepo.UpsertLocalSetting(ctx, settingKeyBackupSchedule, data); err != nil {
+		return err
+	}
+	if err = uc.applySchedule(s); err != nil {
+		return err
+	}
+	uc.bus.PublishTopicEvent(TopicLocalSettingChanged, settingKeyBackupSchedule)
+	return nil
+}
+
+func validateSchedule(s *entity.BackupSchedule) error {
+	if s.KeepCount < 0 || s.KeepDays < 0 {
+		return errors.New("negative keep")
+	}
+	_, err := cron.ParseStandard(s.Spec)
+	return err
+}
+
+// onSettingChanged reloads the schedule or the targets written by others, the same one is not applied again.
+// Skipped in restore, both are reloaded after.
+func (uc *backupUseCase) onSettingChanged(key entity.LocalSettingKey) {
+	if !uc.dbLock.TryRLock() {
+		return
+	}
+	defer uc.dbLock.RUnlock()
+	switch key {
+	case settingKeyBackupSchedule:
+		schedule, err := uc.loadSchedule(uc.ctx)
+		if err != nil {
+			uc.logger.Errorf("Failed to reload backup schedule: %v", err)
+			return
+		}
+		uc.statusLock.RLock()
+		same := *uc.status.Schedule == *schedule
+		uc.statusLock.RUnlock()
+		if same {
+			return
+		}
+		if err = uc.applySchedule(schedule); err != nil {
+			uc.logger.Errorf("Invalid backup schedule %s: %v", schedule.Spec, err)
+		}
+	case settingKeyBackupTargets:
+		if err := uc.loadTargets(uc.ctx); err != nil {
+			uc.logger.Errorf("Failed to reload backup targets: %v", err)
+		}
+	}
+}
+
+// loadSchedule returns the default schedule if never saved.
+func (uc *backupUseCase) loadSchedule(ctx context.Context) (*entity.BackupSchedule, error) {
+	data, err := uc.systemRepo.SelectLocalSetting(ctx, settingKeyBackupSchedule)
+	if err != nil {
+		return nil, err
+	}
+	schedule := defaultBackupSchedule
+	if data == nil {
+		return &schedule, nil
+	}
+	if err = json.Unmarshal(data, &schedule); err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// applySchedule replaces the cron entry, the entry is removed if disabled.
+func (uc *backupUseCase) applySchedule(s *entity.BackupSchedule) error {
+	var entry cron.EntryID
+	if s.Enabled {
+		var err error
+		if entry, err = uc.scheduler.AddFunc(s.Spec, uc.scheduledBackup); err != nil {
+			return err
+		}
+	}
+	uc.statusLock.Lock()
+	defer uc.statusLock.Unlock()
+	if uc.entry != 0 {
+		uc.scheduler.Remove(uc.entry)
+	}
+	uc.entry = entry
+	schedule := *s
+	uc.status.Schedule = &schedule
+	return nil
+}
+
+// scheduledBackup is skipped if the last one is still running.
+func (uc *backupUseCase) scheduledBackup() {
+	if !uc.running.TryLock() {
+		uc.logger.Warn("Scheduled backup in progress, skip")
+		return
+	}
+	defer uc.running.Unlock()
+	if !uc.dbLock.TryRLock() {
+		uc.logger.Warn("Restore in progress, skip scheduled backup")
+		return
+	}
+	defer uc.dbLock.RUnlock()
+
+	uc.setStatus(func(s *entity.BackupScheduleStatus) {
+		s.Running = true
+		s.LastRun = time.Now()
+	})
+	ctx, span := tracer.Start(uc.ctx, "Backup.scheduledBackup")
+	defer span.End()
+	name, pruned, err := uc.backupAndPrune(ctx)
+	uc.setStatus(func(s *entity.BackupScheduleStatus) {
+		s.Running = false
+		s.Pruned = pruned
+		if err != nil {
+			s.LastError = err.Error()
+			return
+		}
+		s.LastError = ""
+		s.LastBackup = name
+	})
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		uc.logger.Errorf("Scheduled backup failed: %v", err)
+		return
+	}
+	uc.logger.Infof("Scheduled backup %s created, %d pruned", name, len(pruned))
+	if err = uc.replicate(ctx, name); err != nil {
+		uc.logger.Errorf("Scheduled backup %s: %v", name, err)
+	}
+}
+
+func (uc *backupUseCase) setStatus(fn func(s *entity.BackupScheduleStatus)) {
+	uc.statusLock.Lock()
+	defer uc.statusLock.Unlock()
+	fn(&uc.status)
+}
+
+// backupAndPrune creates a backup, then deletes the scheduled backups out of retention.
+// Backups created by hand are never pruned.
+func (uc *backupUseCase) backupAndPrune(ctx context.Context) (string, []string, error) {
+	name, err := uc.createBackup(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	after, err := launcher.Get().ListBackups()
+	if err != nil {
+		return name, nil, err
+	}
+
+	scheduled, err := uc.loadScheduled(ctx)
+	if err != nil {
+		return name, nil, err
+	}
+	scheduled[name] = struct{}{}
+	pruned, err := uc.prune(after, scheduled)
+	for _, v := range pruned {
+		delete(scheduled, v)
+	}
+	if sErr := uc.saveScheduled(ctx, after, scheduled); sErr != nil {
+		err = errors.Join(err, sErr)
+	}
+	return name, pruned, err
+}
+
+// prune keeps the newest scheduled backup whatever the retention is.
+func (uc *backupUseCase) prune(all []launcher.Backup, scheduled map[string]struct{}) ([]string, error) {
+	uc.statusLock.RLock()
+	keepCount, keepDays := uc.status.Schedule.KeepCount, uc.status.Schedule.KeepDays
+	uc.statusLock.RUnlock()
+
+	list := []launcher.Backup{}
+	for _, v := range all {
+		if _, ok := scheduled[v.Name]; ok {
+			list = append(list, v)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+	deadline := time.Now().AddDate(0, 0, -keepDays)
+	pruned := []string{}
+	for i, v := range list {
+		if outOfRetention(i, v.CreatedAt, deadline, keepCount, keepDays) {
+			if err := launcher.Get().DeleteBackup(v.Name); err != nil {
+				return pruned, err
+			}
+			pruned = append(pruned, v.Name)
+		}
+	}
+	return pruned, nil
+}
+
+// outOfRetention is true if the i-th newest backup should be deleted, the newest one never.
+func outOfRetention(i int, createdAt, deadline time.Time, keepCount, keepDays int) bool {
+	if i == 0 {
+		return false
+	}
+	return (keepCount > 0 && i >= keepCount) || (keepDays > 0 && createdAt.Before(deadline))
+}
+
+func (uc *backupUseCase) loadScheduled(ctx context.Context) (map[string]struct{}, error) {
+	data, err := uc.systemRepo.SelectLocalSetting(ctx, settingKeyBackupScheduled)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	if data != nil {
+		if err = json.Unmarshal(data, &names); err != nil {
+			return nil, err
+		}
+	}
+	result := make(map[string]struct{}, len(names))
+	for _, v := range names {
+		result[v] = struct{}{}
+	}
+	return result, nil
+}
+
+// saveScheduled drops the names already deleted by hand.
+func (uc *backupUseCase) saveScheduled(ctx context.Context, all []launcher.Backup, scheduled map[string]struct{}) error {
+	names := []string{}
+	for _, v := range all {
+		if _, ok := scheduled[v.Name]; ok {
+			names = append(names, v.Name)
+		}
+	}
+	sort.Strings(names)
+	data, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+	return uc.systemRepo.UpsertLocalSetting(ctx, settingKeyBackupScheduled, data)
+}
+
+func (uc *backupUseCase) CreateBackupJob() (*entity.Job, error) {
+	return uc.startSharedJob(entity.JobKindBackup, "", func(_ string, report func(int, string)) (string, error) {
+		report(10, "dumping database")
+		name, err := uc.createBackup(uc.ctx)
+		if err != nil {
+			return "", err
+		}
+		report(70, "replicating to targets")
+		if err = uc.replicate(uc.ctx, name); err != nil {
+			uc.logger.Errorf("Backup %s: %v", name, err)
+		}
+		return name, nil
+	})
+}
+
+// createBackup dumps the database and writes the manifest, returns the backup name.
+// The dump is encrypted before hashed if the encryption key is set, the plaintext never stays.
+func (uc *backupUseCase) createBackup(ctx context.Context) (_ string, err error) {
+	start := time.Now()
+	defer func() {
+		backupDuration.WithLabelValues(result(err)).Observe(time.Since(start).Seconds())
+	}()
+	dbt := launcher.Get()
+	before, err := dbt.ListBackups()
+	if err != nil {
+		return "", err
+	}
+	if err = dbt.Backup(false); err != nil {
+		return "", err
+	}
+	after, err := dbt.ListBackups()
+	if err != nil {
+		return "", err
+	}
+	backup := newBackup(before, after)
+	if backup == nil {
+		return "", errors.New("backup created but not found")
+	}
+	encrypted := uc.encryptionKey != ""
+	if encrypted {
+		if err = crypt.EncryptDir(backup.Path, uc.encryptionKey, launcherMetaFile, manifest.FileName); err != nil {
+			if dErr := dbt.DeleteBackup(backup.Name); dErr != nil {
+				uc.logger.Errorf("Failed to delete unencrypted backup %s: %v", backup.Name, dErr)
+			}
+			return "", err
+		}
+	}
+	core, local, err := uc.backupRepo.SelectSchemaVersion(ctx)
+	if err != nil {
+		return backup.Name, err
+	}
+	counts, err := uc.backupRepo.CountRows(ctx)
+	if err != nil {
+		return backup.Name, err
+	}
+	files, size, sum, err := manifest.Hash(backup.Path)
+	if err != nil {
+		return backup.Name, err
+	}
+	backupSize.Set(float64(size))
+	build := version.GetCore()
+	return backup.Name, manifest.Write(backup.Path, &entity.BackupManifest{
+		Name:           backup.Name,
+		AppVersion:     build.GetVersion(),
+		AppCommit:      build.GetCommit(),
+		Migration:      core,
+		LocalMigration: local,
+		Size:           size,
+		SHA256:         sum,
+		Files:          files,
+		RowCounts:      counts,
+		Encrypted:      encrypted,
+		CreatedAt:      backup.CreatedAt,
+	})
+}
+
+// newBackup returns the backup in after but not in before, nil if none.
+func newBackup(before, after []launcher.Backup) *launcher.Backup {
+	exists := make(map[string]struct{}, len(before))
+	for _, v := range before {
+		exists[v.Name] = struct{}{}
+	}
+	for _, v := range after {
+		if _, ok := exists[v.Name]; !ok {
+			return &v
+		}
+	}
+	return nil
+}
+
+// checkBackup verifies the checksums and that the schema is not newer than the running one.
+func (uc *backupUseCase) checkBackup(ctx context.Context, path string) (*entity.BackupManifest, error) {
+	m, err := manifest.Read(path)
+	if err != nil {
+		if errors.Is(err, manifest.ErrNotFound) {
+			return nil, ErrBackupManifestMissing
+		}
+		if errors.Is(err, manifest.ErrCorrupted) {
+			return nil, ErrBackupCorrupted
+		}
+		return nil, err
+	}
+	if err = manifest.Verify(path, m); err != nil {
+		uc.logger.Warnf("Backup %s: %v", m.Name, err)
+		return m, ErrBackupCorrupted
+	}
+	core, local, err := uc.backupRepo.SelectSchemaVersion(ctx)
+	if err != nil {
+		return m, err
+	}
+	if m.Migration > core || m.LocalMigration > local {
+		uc.logger.Warnf("Backup %s schema %d/%d is newer than %d/%d",
+			m.Name, m.Migration, m.LocalMigration, core, local)
+		return m, ErrBackupSchemaMismatch
+	}
+	return m, nil
+}
+
+// CreateRestoreJob restores in process, capitan is in maintenance until the use cases rebuilt.
+func (uc *backupUseCase) CreateRestoreJob(name string) (*entity.Job, error) {
+	backup, err := uc.findBackup(name)
+	if err != nil {
+		return nil, err
+	}
+	if !uc.dbLock.TryRLock() {
+		return nil, ErrRestoreInProgress
+	}
+	m, err := uc.checkBackup(uc.ctx, backup.Path)
+	uc.dbLock.RUnlock()
+	if err != nil {
+		if !errors.Is(err, ErrBackupManifestMissing) {
+			return nil, err
+		}
+		uc.logger.Warnf("Backup %s has no manifest, restore without verification", name)
+	}
+	encrypted := m != nil && m.Encrypted
+	if encrypted && uc.encryptionKey == "" {
+		return nil, ErrBackupKeyRequired
+	}
+	if !uc.restoring.TryLock() {
+		return nil, ErrRestoreInProgress
+	}
+	job, err := uc.newJob(entity.JobKindRestore, name)
+	if err != nil {
+		uc.restoring.Unlock()
+		return nil, err
+	}
+	go func() {
+		defer uc.restoring.Unlock()
+		uc.runJob(job.ID, func(_ string, report func(int, string)) (string, error) {
+			report(5, "waiting for running jobs")
+			uc.dbLock.Lock()
+			defer uc.dbLock.Unlock()
+			return "", uc.restoreBackup(backup, encrypted, report)
+		})
+	}()
+	return job, nil
+}
+
+// restoreBackup opens the encrypted backup in place for the restore, the plaintext is removed after.
+func (uc *backupUseCase) restoreBackup(backup *launcher.Backup, encrypted bool, report func(int, string)) error {
+	if encrypted {
+		report(8, "decrypting backup")
+		opened, err := uc.decryptBackup(backup.Path, backup.Path)
+		if err != nil {
+			return err
+		}
+		defer removeFiles(opened)
+	}
+	return uc.restore(backup.Name, report)
+}
+
+// restore drains the traffic, restores the database, then resumes with the pool and use cases rebuilt.
+// Resume is tried even if restore failed, the database is cleared by then.
+func (uc *backupUseCase) restore(name string, report func(int, string)) error {
+	report(10, "draining connections")
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(uc.ctx), restoreDrainTimeout)
+	defer cancel()
+	if err := uc.maintainer.Drain(ctx); err != nil {
+		uc.logger.Warnf("Drain not finished in %s: %v, restore anyway", restoreDrainTimeout, err)
+	}
+
+	report(30, "restoring database")
+	restoreErr := launcher.Get().RestoreDatabase(name)
+	if restoreErr != nil {
+		uc.logger.Errorf("Restore %s failed: %v", name, restoreErr)
+	}
+
+	report(70, "migrating and resuming")
+	if err := uc.maintainer.Resume(uc.rebind); err != nil {
+		return errors.Join(restoreErr, fmt.Errorf("resume failed: %w", err))
+	}
+	schedule, err := uc.loadSchedule(uc.ctx)
+	if err != nil {
+		return errors.Join(restoreErr, err)
+	}
+	if err = uc.applySchedule(schedule); err != nil {
+		uc.logger.Errorf("Invalid restored backup schedule %s: %v", schedule.Spec, err)
+	}
+	if err = uc.loadTargets(uc.ctx); err != nil {
+		return errors.Join(restoreErr, err)
+	}
+	return restoreErr
+}
+
+// rebind binds the repos to the reopened pool, before the traffic served again.
+func (uc *backupUseCase) rebind() {
+	pool := config.Get().GetPostgresPool()
+	uc.systemRepo = repo.NewSystemRepo(pool)
+	uc.backupRepo = repo.NewBackupRepo(pool)
+}
+
+// startUploadJob loads the uploaded archive at path, the file is removed after the job started.
+// The archive sealed by passphrase is opened first, target is the name shown in the job.
+func (uc *backupUseCase) startUploadJob(path, target, passphrase string) (*entity.Job, error) {
+	encrypted, err := crypt.IsEncrypted(path)
+	if err != nil {
+		return nil, err
+	}
+	if encrypted && passphrase == "" {
+		return nil, ErrBackupKeyRequired
+	}
+	return uc.startSharedJob(entity.JobKindUpload, target, func(_ string, report func(int, string)) (string, error) {
+		defer func() {
+			_ = os.Remove(path)
+		}()
+		archive := path
+		if encrypted {
+			report(5, "decrypting archive")
+			archive = path + ".zip"
+			if dErr := crypt.DecryptFile(path, archive, passphrase); dErr != nil {
+				if errors.Is(dErr, crypt.ErrDecrypt) {
+					return "", ErrBackupDecrypt
+				}
+				return "", dErr
+			}
+			defer func() {
+				_ = os.Remove(archive)
+			}()
+		}
+		dbt := launcher.Get()
+		before, err := dbt.ListBackups()
+		if err != nil {
+			return "", err
+		}
+		report(10, "extracting archive")
+		if err = dbt.LoadBackupArchiveFile(archive); err != nil {
+			return "", err
+		}
+		after, err := dbt.ListBackups()
+		if err != nil {
+			return "", err
+		}
+		backup := newBackup(before, after)
+		if backup == nil {
+			return "", errors.New("archive loaded but not found")
+		}
+		report(60, "verifying checksums")
+		if _, err = uc.checkBackup(uc.ctx, backup.Path); err != nil {
+			if dErr := dbt.DeleteBackup(backup.Name); dErr != nil {
+				uc.logger.Errorf("Failed to delete rejected backup %s: %v", backup.Name, dErr)
+			}
+			return "", err
+		}
+		return backup.Name, nil
+	})
+}
+
+// CreateVerifyJob checks the manifest, then restores into a scratch database and compares row counts.
+func (uc *backupUseCase) CreateVerifyJob(name string) (*entity.Job, error) {
+	backup, err := uc.findBackup(name)
+	if err != nil {
+		return nil, err
+	}
+	return uc.startSharedJob(entity.JobKindVerify, name, func(id string, report func(int, string)) (string, error) {
+		return "", uc.verify(id, backup, report)
+	})
+}
+
+// verify keeps the report in the job whatever the result is.
+func (uc *backupUseCase) verify(id string, backup *launcher.Backup, report func(int, string)) error {
+	ctx := uc.ctx
+	result := &entity.BackupVerifyReport{
+		RowCounts:  map[string]int64{},
+		Mismatches: []string{},
+	}
+	defer uc.transitJob(id, entity.JobStateRunning, func(j *entity.Job) {
+		j.Report = result
+	})
+
+	report(10, "verifying checksums")
+	m, err := uc.checkBackup(ctx, backup.Path)
+	result.Files = m != nil && !errors.Is(err, ErrBackupCorrupted)
+	if err != nil {
+		return err
+	}
+	result.Schema = true
+
+	restorePath := backup.Path
+	if m.Encrypted {
+		report(20, "decrypting backup")
+		if restorePath, err = os.MkdirTemp("", scratchDatabasePrefix); err != nil {
+			return err
+		}
+		defer func() {
+			_ = os.RemoveAll(restorePath)
+		}()
+		if _, err = uc.decryptBackup(backup.Path, restorePath); err != nil {
+			return err
+		}
+	}
+
+	report(30, "restoring into scratch database")
+	scratch := fmt.Sprintf("%s_%d", scratchDatabasePrefix, time.Now().Unix())
+	if err = uc.backupRepo.CreateDatabase(ctx, scratch); err != nil {
+		return err
+	}
+	defer func() {
+		if dErr := uc.backupRepo.DropDatabase(ctx, scratch); dErr != nil {
+			uc.logger.Errorf("Failed to drop scratch database %s: %v", scratch, dErr)
+		}
+	}()
+	cfg := config.Get()
+	bin := "pg_restore"
+	if cfg.Database.BinPath != "" {
+		bin = filepath.Join(cfg.Database.BinPath, bin)
+	}
+	args := append([]string{"--no-owner", "--exit-on-error"}, cfg.DatabaseArgs(scratch)...)
+	cmd := exec.CommandContext(ctx, bin, append(args, restorePath)...)
+	cmd.Env = cfg.DatabaseEnv()
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("dry-run restore failed: %w: %s", err, output)
+	}
+	result.Restore = true
+
+	report(80, "counting rows")
+	pg, err := client.New(cfg.DatabaseURL(scratch), client.MaxPoolSize(1), client.AddLogger(uc.logger))
+	if err != nil {
+		return err
+	}
+	defer pg.Close()
+	if result.RowCounts, err = repo.NewBackupRepo(pg).CountRows(ctx); err != nil {
+		return err
+	}
+	for table, want := range m.RowCounts {
+		if got := result.RowCounts[table]; got != want {
+			result.Mismatches = append(result.Mismatches, fmt.Sprintf("%s: manifest %d, restored %d", table, want, got))
+		}
+	}
+	sort.Strings(result.Mismatches)
+	return nil
+}
+
+// decryptBackup opens the sealed files of the backup at path into dst by the encryption key.
+func (uc *backupUseCase) decryptBackup(path, dst string) ([]string, error) {
+	if uc.encryptionKey == "" {
+		return nil, ErrBackupKeyRequired
+	}
+	opened, err := crypt.DecryptDir(path, dst, uc.encryptionKey)
+	if errors.Is(err, crypt.ErrDecrypt) {
+		return nil, ErrBackupDecrypt
+	}
+	return opened, err
+}
+
+func removeFiles(paths []string) {
+	for _, v := range paths {
+		_ = os.Remove(v)
+	}
+}
+
+// startSharedJob runs fn in background, the jobs except restore can run together.
+func (uc *backupUseCase) startSharedJob(
+	kind entity.JobKind,
+	target string,
+	fn func(id string, report func(progress int, message string)) (string, error),
+) (*entity.Job, error) {
+	if !uc.dbLock.TryRLock() {
+		return nil, ErrRestoreInProgress
+	}
+	job, err := uc.newJob(kind, target)
+	if err != nil {
+		uc.dbLock.RUnlock()
+		return nil, err
+	}
+	go func() {
+		defer uc.dbLock.RUnlock()
+		uc.runJob(job.ID, fn)
+	}()
+	return job, nil
+}
+
+func (uc *backupUseCase) GetJob(id string) (*entity.Job, error) {
+	uc.jobLock.Lock()
+	defer uc.jobLock.Unlock()
+	job := uc.findJob(id)
+	if job == nil {
+		return nil, ErrJobNotFound
+	}
+	result := *job
+	return &result, nil
+}
+
+// ListJobs returns the jobs, newest first.
+func (uc *backupUseCase) ListJobs() []*entity.Job {
+	uc.jobLock.Lock()
+	defer uc.jobLock.Unlock()
+	result := make([]*entity.Job, 0, len(uc.jobs))
+	for i := len(uc.jobs) - 1; i >= 0; i-- {
+		job := *uc.jobs[i]
+		result = append(result, &job)
+	}
+	return result
+}
+
+// SubscribeJob sends the current job at once, then every update until the job finished or ctx done.
+func (uc *backupUseCase) SubscribeJob(ctx context.Context, id string) (<-chan *entity.Job, error) {
+	uc.jobLock.Lock()
+	defer uc.jobLock.Unlock()
+	job := uc.findJob(id)
+	if job == nil {
+		return nil, ErrJobNotFound
+	}
+	ch := make(chan *entity.Job, 16)
+	current := *job
+	ch <- &current
+	if job.State.Finished() {
+		close(ch)
+		return ch, nil
+	}
+	uc.jobSubs[id] = append(uc.jobSubs[id], ch)
+	go func() {
+		<-ctx.Done()
+		uc.jobLock.Lock()
+		defer uc.jobLock.Unlock()
+		for i, v := range uc.jobSubs[id] {
+			if v == ch {
+				uc.jobSubs[id] = append(uc.jobSubs[id][:i], uc.jobSubs[id][i+1:]...)
+				close(ch)
+				break
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func (uc *backupUseCase) findBackup(name string) (*launcher.Backup, error) {
+	list, err := launcher.Get().ListBackups()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range list {
+		if v.Name == name {
+			return &v, nil
+		}
+	}
+	return nil, ErrBackupNotFound
+}
+
+// runJob returns true if the job is done.
+func (uc *backupUseCase) runJob(id string, fn func(id string, report func(progress int, message string)) (string, error)) bool {
+	uc.transitJob(id, entity.JobStateRunning, func(j *entity.Job) {
+		j.Progress = 0
+	})
+	artifact, err := fn(id, func(progress int, message string) {
+		uc.transitJob(id, entity.JobStateRunning, func(j *entity.Job) {
+			j.Progress = progress
+			j.Message = message
+		})
+	})
+	if err != nil {
+		uc.logger.Errorf("Backup job %s failed: %v", id, err)
+		uc.transitJob(id, entity.JobStateFailed, func(j *entity.Job) {
+			j.Error = err.Error()
+		})
+		return false
+	}
+	uc.transitJob(id, entity.JobStateDone, func(j *entity.Job) {
+		j.Progress = 100
+		j.Message = ""
+		j.Artifact = artifact
+	})
+	return true
+}
+
+func (uc *backupUseCase) newJob(kind entity.JobKind, target string) (*entity.Job, error) {
+	now := time.Now()
+	job := &entity.Job{
+		ID:        uuid.New().String(),
+		Kind:      kind,
+		State:     entity.JobStateQueued,
+		Target:    target,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	uc.jobLock.Lock()
+	defer uc.jobLock.Unlock()
+	uc.jobs = append(uc.jobs, job)
+	uc.trimJobs()
+	if err := uc.saveJobs(); err != nil {
+		uc.jobs = uc.jobs[:len(uc.jobs)-1]
+		return nil, err
+	}
+	result := *job
+	return &result, nil
+}
+
+// transitJob applies fn and moves the job to state, running to running is allowed for progress.
+func (uc *backupUseCase) transitJob(id string, state entity.JobState, fn func(j *entity.Job)) {
+	uc.jobLock.Lock()
+	defer uc.jobLock.Unlock()
+	job := uc.findJob(id)
+	if job == nil {
+		return
+	}
+	if job.State != state && !job.State.CanTransit(state) {
+		uc.logger.Errorf("Backup job %s can not transit from %s to %s", id, job.State, state)
+		return
+	}
+	job.State = state
+	job.UpdatedAt = time.Now()
+	fn(job)
+	if err := uc.saveJobs(); err != nil {
+		uc.logger.Errorf("Failed to save backup jobs: %v", err)
+	}
+	for _, ch := range uc.jobSubs[id] {
+		current := *job
+		select {
+		case ch <- &current:
+		default:
+		}
+		if state.Finished() {
+			close(ch)
+		}
+	}
+	if state.Finished() {
+		delete(uc.jobSubs, id)
+	}
+}
+
+func (uc *backupUseCase) findJob(id string) *entity.Job {
+	for _, v := range uc.jobs {
+		if v.ID == id {
+			return v
+		}
+	}
+	return nil
+}
+
+// trimJobs drops the oldest finished jobs over maxKeptJobs.
+func (uc *backupUseCase) trimJobs() {
+	overflow := len(uc.jobs) - maxKeptJobs
+	if overflow <= 0 {
+		return
+	}
+	kept := make([]*entity.Job, 0, len(uc.jobs))
+	for _, v := range uc.jobs {
+		if overflow > 0 && v.State.Finished() {
+			overflow--
+			continue
+		}
+		kept = append(kept, v)
+	}
+	uc.jobs = kept
+}
+
+// loadJobs marks the jobs interrupted by the last shutdown failed.
+func (uc *backupUseCase) loadJobs() error {
+	data, err := os.ReadFile(uc.jobPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if err = json.Unmarshal(data, &uc.jobs); err != nil {
+		return err
+	}
+	for _, v := range uc.jobs {
+		if !v.State.Finished() {
+			v.State = entity.JobStateFailed
+			v.Error = "interrupted by shutdown"
+			v.UpdatedAt = time.Now()
+		}
+	}
+	return uc.saveJobs()
+}
+
+// saveJobs writes the job file atomically, caller must hold jobLock.
+func (uc *backupUseCase) saveJobs() error {
+	data, err := json.Marshal(uc.jobs)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(uc.jobPath), os.ModePerm); err != nil {
+		return err
+	}
+	tmp := uc.jobPath + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, uc.jobPath)
+}
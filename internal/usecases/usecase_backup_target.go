@@ -0,0 +1,400 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chindada/capitan/internal/usecases/entity"
+	"github.com/chindada/capitan/internal/usecases/modules/storage"
+	"github.com/chindada/panther/pkg/launcher"
+)
+
+// remoteArchiveExt is appended to the backup name of the archive on the targets.
+const remoteArchiveExt = ".zip"
+
+// GetTargets returns the targets without secrets and the latest replication results.
+func (uc *backupUseCase) GetTargets() []*entity.BackupTargetStatus {
+	uc.targetLock.RLock()
+	defer uc.targetLock.RUnlock()
+	result := make([]*entity.BackupTargetStatus, 0, len(uc.targets))
+	for _, v := range uc.targets {
+		target := *v
+		redactTargets([]*entity.BackupTarget{&target})
+		status := entity.BackupTargetStatus{Pruned: []string{}}
+		if last, ok := uc.replications[v.Name]; ok {
+			status = *last
+			status.Pruned = append([]string{}, last.Pruned...)
+		}
+		status.Target = &target
+		result = append(result, &status)
+	}
+	return result
+}
+
+// UpdateTargets replaces all targets, the empty secrets are taken from the saved target of the same name and kind.
+func (uc *backupUseCase) UpdateTargets(ctx context.Context, targets []*entity.BackupTarget) error {
+	uc.targetLock.RLock()
+	keepTargetSecrets(targets, uc.targets)
+	uc.targetLock.RUnlock()
+
+	if err := validateTargets(targets); err != nil {
+		uc.logger.Warnf("Backup target %v", err)
+		return ErrBackupTargetInvalid
+	}
+	data, err := json.Marshal(targets)
+	if err != nil {
+		return err
+	}
+	if !uc.dbLock.TryRLock() {
+		return ErrRestoreInProgress
+	}
+	defer uc.dbLock.RUnlock()
+	if err = uc.systemRepo.UpsertLocalSetting(ctx, settingKeyBackupTargets, data); err != nil {
+		return err
+	}
+	uc.setTargets(targets)
+	uc.bus.PublishTopicEvent(TopicLocalSettingChanged, settingKeyBackupTargets)
+	return nil
+}
+
+// keepTargetSecrets fills the empty secrets from the saved target of the same name and kind.
+func keepTargetSecrets(targets, saved []*entity.BackupTarget) {
+	byName := make(map[string]*entity.BackupTarget, len(saved))
+	for _, v := range saved {
+		byName[v.Name] = v
+	}
+	for _, t := range targets {
+		old, ok := byName[t.Name]
+		if !ok || old.Kind != t.Kind {
+			continue
+		}
+		if t.Password == "" {
+			t.Password = old.Password
+		}
+		if t.PrivateKey == "" {
+			t.PrivateKey = old.PrivateKey
+		}
+		if t.SecretKey == "" {
+			t.SecretKey = old.SecretKey
+		}
+	}
+}
+
+// redactTargets clears the secrets of targets in place.
+func redactTargets(targets []*entity.BackupTarget) {
+	for _, t := range targets {
+		t.Password = ""
+		t.PrivateKey = ""
+		t.SecretKey = ""
+	}
+}
+
+func validateTargets(targets []*entity.BackupTarget) error {
+	names := make(map[string]struct{}, len(targets))
+	for _, t := range targets {
+		if err := storage.Validate(t); err != nil {
+			return fmt.Errorf("%s: %w", t.Name, err)
+		}
+		if _, ok := names[t.Name]; ok {
+			return fmt.Errorf("%s: duplicated", t.Name)
+		}
+		names[t.Name] = struct{}{}
+	}
+	return nil
+}
+
+func (uc *backupUseCase) loadTargets(ctx context.Context) error {
+	data, err := uc.systemRepo.SelectLocalSetting(ctx, settingKeyBackupTargets)
+	if err != nil {
+		return err
+	}
+	targets := []*entity.BackupTarget{}
+	if data != nil {
+		if err = json.Unmarshal(data, &targets); err != nil {
+			return err
+		}
+	}
+	uc.setTargets(targets)
+	return nil
+}
+
+// setTargets drops the replication results of the removed targets.
+func (uc *backupUseCase) setTargets(targets []*entity.BackupTarget) {
+	uc.targetLock.Lock()
+	defer uc.targetLock.Unlock()
+	uc.targets = targets
+	kept := make(map[string]*entity.BackupTargetStatus, len(targets))
+	for _, v := range targets {
+		if last, ok := uc.replications[v.Name]; ok {
+			kept[v.Name] = last
+		}
+	}
+	uc.replications = kept
+}
+
+func (uc *backupUseCase) findTarget(name string) (*entity.BackupTarget, error) {
+	uc.targetLock.RLock()
+	defer uc.targetLock.RUnlock()
+	for _, v := range uc.targets {
+		if v.Name == name {
+			target := *v
+			return &target, nil
+		}
+	}
+	return nil, ErrBackupTargetNotFound
+}
+
+func (uc *backupUseCase) enabledTargets() []*entity.BackupTarget {
+	uc.targetLock.RLock()
+	defer uc.targetLock.RUnlock()
+	result := []*entity.BackupTarget{}
+	for _, v := range uc.targets {
+		if v.Enabled {
+			target := *v
+			result = append(result, &target)
+		}
+	}
+	return result
+}
+
+// replicate uploads the backup to every enabled target, then prunes the target by its retention.
+// A failed target does not stop the others, the backup stays on the local disk anyway.
+func (uc *backupUseCase) replicate(ctx context.Context, name string) error {
+	targets := uc.enabledTargets()
+	if len(targets) == 0 {
+		return nil
+	}
+	backup, err := uc.findBackup(name)
+	if err != nil {
+		return err
+	}
+	dir, err := os.MkdirTemp("", "capitan_replicate")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	archive := filepath.Join(dir, name+remoteArchiveExt)
+	if err = launcher.Get().Zip(archive, backup.Path); err != nil {
+		return err
+	}
+	errs := []error{}
+	for _, t := range targets {
+		pruned, rErr := uc.replicateTo(ctx, t, archive)
+		uc.setReplication(t.Name, name, pruned, rErr)
+		if rErr != nil {
+			errs = append(errs, fmt.Errorf("replicate to %s: %w", t.Name, rErr))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (uc *backupUseCase) replicateTo(ctx context.Context, t *entity.BackupTarget, archive string) ([]string, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	st, err := storage.New(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = st.Close()
+	}()
+	if err = st.Put(ctx, filepath.Base(archive), f, info.Size()); err != nil {
+		return nil, err
+	}
+	return uc.pruneRemote(ctx, t, st)
+}
+
+// pruneRemote applies the target retention to all archives on it, the newest is always kept.
+func (uc *backupUseCase) pruneRemote(ctx context.Context, t *entity.BackupTarget, st storage.Target) ([]string, error) {
+	objects, err := st.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	archives := []storage.Object{}
+	for _, v := range objects {
+		if strings.HasSuffix(v.Name, remoteArchiveExt) {
+			archives = append(archives, v)
+		}
+	}
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].ModTime.After(archives[j].ModTime)
+	})
+	deadline := time.Now().AddDate(0, 0, -t.KeepDays)
+	pruned := []string{}
+	for i, v := range archives {
+		if !outOfRetention(i, v.ModTime, deadline, t.KeepCount, t.KeepDays) {
+			continue
+		}
+		if err = st.Delete(ctx, v.Name); err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, strings.TrimSuffix(v.Name, remoteArchiveExt))
+	}
+	return pruned, nil
+}
+
+func (uc *backupUseCase) setReplication(target, backup string, pruned []string, err error) {
+	status := &entity.BackupTargetStatus{
+		LastRun:    time.Now(),
+		LastBackup: backup,
+		Pruned:     pruned,
+	}
+	if status.Pruned == nil {
+		status.Pruned = []string{}
+	}
+	if err != nil {
+		status.LastError = err.Error()
+	}
+	uc.targetLock.Lock()
+	defer uc.targetLock.Unlock()
+	uc.replications[target] = status
+}
+
+// ListRemoteBackups returns the archives on the target, newest first.
+func (uc *backupUseCase) ListRemoteBackups(ctx context.Context, target string) ([]*entity.RemoteBackup, error) {
+	t, err := uc.findTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	st, err := storage.New(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = st.Close()
+	}()
+	objects, err := st.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := []*entity.RemoteBackup{}
+	for _, v := range objects {
+		if !strings.HasSuffix(v.Name, remoteArchiveExt) {
+			continue
+		}
+		result = append(result, &entity.RemoteBackup{
+			Target:    target,
+			Name:      strings.TrimSuffix(v.Name, remoteArchiveExt),
+			Size:      v.Size,
+			CreatedAt: v.ModTime,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// CreateRemoteRestoreJob fetches the backup from the target if not on the local disk, then restores it.
+func (uc *backupUseCase) CreateRemoteRestoreJob(target, name string) (*entity.Job, error) {
+	t, err := uc.findTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	if !uc.restoring.TryLock() {
+		return nil, ErrRestoreInProgress
+	}
+	job, err := uc.newJob(entity.JobKindRestore, fmt.Sprintf("%s/%s", target, name))
+	if err != nil {
+		uc.restoring.Unlock()
+		return nil, err
+	}
+	go func() {
+		defer uc.restoring.Unlock()
+		uc.runJob(job.ID, func(_ string, report func(int, string)) (string, error) {
+			report(2, "waiting for running jobs")
+			uc.dbLock.Lock()
+			defer uc.dbLock.Unlock()
+			report(4, "fetching backup")
+			backup, fErr := uc.fetch(uc.ctx, t, name)
+			if fErr != nil {
+				return "", fErr
+			}
+			m, cErr := uc.checkBackup(uc.ctx, backup.Path)
+			if cErr != nil {
+				if !errors.Is(cErr, ErrBackupManifestMissing) {
+					return "", cErr
+				}
+				uc.logger.Warnf("Backup %s has no manifest, restore without verification", name)
+			}
+			return "", uc.restoreBackup(backup, m != nil && m.Encrypted, report)
+		})
+	}()
+	return job, nil
+}
+
+// fetch downloads the archive from the target and loads it, the local backup is used if exists.
+func (uc *backupUseCase) fetch(ctx context.Context, t *entity.BackupTarget, name string) (*launcher.Backup, error) {
+	if backup, err := uc.findBackup(name); err == nil {
+		return backup, nil
+	} else if !errors.Is(err, ErrBackupNotFound) {
+		return nil, err
+	}
+	st, err := storage.New(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = st.Close()
+	}()
+	src, err := st.Get(ctx, name+remoteArchiveExt)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrBackupNotFound
+		}
+		return nil, err
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+	tmp, err := os.CreateTemp("", "capitan_fetch_*"+remoteArchiveExt)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	_, err = io.Copy(tmp, src)
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	dbt := launcher.Get()
+	before, err := dbt.ListBackups()
+	if err != nil {
+		return nil, err
+	}
+	if err = dbt.LoadBackupArchiveFile(tmp.Name()); err != nil {
+		return nil, err
+	}
+	after, err := dbt.ListBackups()
+	if err != nil {
+		return nil, err
+	}
+	backup := newBackup(before, after)
+	if backup == nil {
+		return nil, errors.New("archive loaded but not found")
+	}
+	return backup, nil
+}
@@ -0,0 +1,371 @@
+package usecases
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chindada/capitan/internal/usecases/entity"
+	"github.com/chindada/capitan/internal/usecases/modules/archive"
+	"github.com/chindada/capitan/internal/usecases/modules/crypt"
+	"github.com/google/uuid"
+)
+
+const (
+	// uploadExpiry is the time an unfinished upload session is kept.
+	uploadExpiry = 24 * time.Hour
+	// maxFileNameLength is the max length of the sanitized upload file name.
+	maxFileNameLength = 128
+)
+
+// BackupArchive is the zip of a backup being downloaded, the backup can not be restored until closed.
+type BackupArchive struct {
+	FileName string
+	ModTime  time.Time
+	ETag     string
+
+	archive    *archive.Archive
+	passphrase string
+	reader     io.ReadSeekCloser
+	release    sync.Once
+	unlock     func()
+}
+
+// Seekable is false if sealed by passphrase, the random salt makes every download different.
+func (a *BackupArchive) Seekable() bool {
+	return a.passphrase == ""
+}
+
+// Reader returns the zip for range requests, only if seekable.
+func (a *BackupArchive) Reader() io.ReadSeeker {
+	if a.reader == nil {
+		a.reader = a.archive.NewReader()
+	}
+	return a.reader
+}
+
+// WriteTo writes the whole zip, sealed if the passphrase given.
+func (a *BackupArchive) WriteTo(w io.Writer) (int64, error) {
+	if a.passphrase == "" {
+		return a.archive.WriteTo(w)
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := a.archive.WriteTo(pw)
+		_ = pw.CloseWithError(err)
+	}()
+	defer func() {
+		_ = pr.Close()
+	}()
+	counter := &countingWriter{w: w}
+	err := crypt.Encrypt(counter, pr, a.passphrase)
+	return counter.n, err
+}
+
+func (a *BackupArchive) Close() {
+	a.release.Do(func() {
+		if a.reader != nil {
+			_ = a.reader.Close()
+		}
+		a.unlock()
+	})
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// OpenArchive streams the backup as zip, the files encrypted at rest stay sealed by the key.
+func (uc *backupUseCase) OpenArchive(name, passphrase string) (*BackupArchive, error) {
+	if !uc.dbLock.TryRLock() {
+		return nil, ErrRestoreInProgress
+	}
+	backup, err := uc.findBackup(name)
+	if err != nil {
+		uc.dbLock.RUnlock()
+		return nil, err
+	}
+	a, err := archive.New(backup.Path)
+	if err != nil {
+		uc.dbLock.RUnlock()
+		return nil, err
+	}
+	fileName := filepath.Base(backup.Path) + remoteArchiveExt
+	if passphrase != "" {
+		fileName += crypt.Ext
+	}
+	return &BackupArchive{
+		FileName:   fileName,
+		ModTime:    a.ModTime(),
+		ETag:       a.ETag(),
+		archive:    a,
+		passphrase: passphrase,
+		unlock:     uc.dbLock.RUnlock,
+	}, nil
+}
+
+// UploadBackup streams src into the upload directory, then loads it in background.
+func (uc *backupUseCase) UploadBackup(fileName string, src io.Reader, passphrase string) (*entity.Job, error) {
+	if err := os.MkdirAll(uc.uploadPath, 0o750); err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp(uc.uploadPath, "upload_*.part")
+	if err != nil {
+		return nil, err
+	}
+	n, err := io.Copy(f, io.LimitReader(src, uc.uploadMaxSize+1))
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil && n > uc.uploadMaxSize {
+		err = ErrUploadTooLarge
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return nil, err
+	}
+	job, err := uc.startUploadJob(f.Name(), sanitizeFileName(fileName), passphrase)
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return nil, err
+	}
+	return job, nil
+}
+
+// uploadSession is kept as id.json beside id.part, the offset is the size of the part file.
+type uploadSession struct {
+	lock    sync.Mutex
+	session entity.UploadSession
+}
+
+func (uc *backupUseCase) CreateUploadSession(fileName string, size int64) (*entity.UploadSession, error) {
+	if size <= 0 || size > uc.uploadMaxSize {
+		return nil, ErrUploadTooLarge
+	}
+	if err := os.MkdirAll(uc.uploadPath, 0o750); err != nil {
+		return nil, err
+	}
+	uc.expireUploads()
+	s := &uploadSession{
+		session: entity.UploadSession{
+			ID:        uuid.New().String(),
+			FileName:  sanitizeFileName(fileName),
+			Size:      size,
+			CreatedAt: time.Now(),
+		},
+	}
+	if err := os.WriteFile(uc.uploadPartPath(s.session.ID), nil, 0o600); err != nil {
+		return nil, err
+	}
+	if err := uc.saveUpload(&s.session); err != nil {
+		_ = os.Remove(uc.uploadPartPath(s.session.ID))
+		return nil, err
+	}
+	uc.uploadLock.Lock()
+	uc.uploads[s.session.ID] = s
+	uc.uploadLock.Unlock()
+	result := s.session
+	return &result, nil
+}
+
+func (uc *backupUseCase) GetUploadSession(id string) (*entity.UploadSession, error) {
+	s := uc.findUpload(id)
+	if s == nil {
+		return nil, ErrUploadNotFound
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	result := s.session
+	return &result, nil
+}
+
+// AppendUploadSession writes chunk at offset, which must be the received bytes.
+// The bytes received before a broken chunk are kept, the client resumes from the returned offset.
+// The archive is loaded once all bytes received, an empty chunk at the end retries the load.
+func (uc *backupUseCase) AppendUploadSession(id string, offset int64, chunk io.Reader, passphrase string) (*entity.UploadSession, error) {
+	s := uc.findUpload(id)
+	if s == nil {
+		return nil, ErrUploadNotFound
+	}
+	if !s.lock.TryLock() {
+		return nil, ErrUploadOffsetMismatch
+	}
+	defer s.lock.Unlock()
+	if offset != s.session.Offset {
+		return nil, ErrUploadOffsetMismatch
+	}
+
+	path := uc.uploadPartPath(id)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	remain := s.session.Size - s.session.Offset
+	n, err := io.Copy(f, io.LimitReader(chunk, remain+1))
+	if err == nil && n > remain {
+		err = ErrUploadTooLarge
+		n = remain
+		if tErr := f.Truncate(s.session.Size); tErr != nil {
+			err = errors.Join(err, tErr)
+		}
+	}
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	s.session.Offset += n
+	if err != nil {
+		result := s.session
+		return &result, err
+	}
+	if s.session.Offset < s.session.Size {
+		result := s.session
+		return &result, nil
+	}
+
+	job, err := uc.startUploadJob(path, s.session.FileName, passphrase)
+	if err != nil {
+		result := s.session
+		return &result, err
+	}
+	s.session.JobID = job.ID
+	_ = os.Remove(uc.uploadMetaPath(id))
+	uc.uploadLock.Lock()
+	delete(uc.uploads, id)
+	uc.uploadLock.Unlock()
+	result := s.session
+	return &result, nil
+}
+
+func (uc *backupUseCase) DeleteUploadSession(id string) error {
+	s := uc.findUpload(id)
+	if s == nil {
+		return ErrUploadNotFound
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	uc.removeUpload(id)
+	return nil
+}
+
+func (uc *backupUseCase) findUpload(id string) *uploadSession {
+	uc.uploadLock.Lock()
+	defer uc.uploadLock.Unlock()
+	return uc.uploads[id]
+}
+
+func (uc *backupUseCase) removeUpload(id string) {
+	uc.uploadLock.Lock()
+	delete(uc.uploads, id)
+	uc.uploadLock.Unlock()
+	_ = os.Remove(uc.uploadPartPath(id))
+	_ = os.Remove(uc.uploadMetaPath(id))
+}
+
+// expireUploads removes the sessions not finished in uploadExpiry.
+func (uc *backupUseCase) expireUploads() {
+	uc.uploadLock.Lock()
+	expired := []string{}
+	for id, s := range uc.uploads {
+		if s.lock.TryLock() {
+			if time.Since(s.session.CreatedAt) > uploadExpiry {
+				expired = append(expired, id)
+			}
+			s.lock.Unlock()
+		}
+	}
+	uc.uploadLock.Unlock()
+	for _, id := range expired {
+		uc.removeUpload(id)
+	}
+}
+
+func (uc *backupUseCase) uploadPartPath(id string) string {
+	return filepath.Join(uc.uploadPath, id+".part")
+}
+
+func (uc *backupUseCase) uploadMetaPath(id string) string {
+	return filepath.Join(uc.uploadPath, id+".json")
+}
+
+func (uc *backupUseCase) saveUpload(s *entity.UploadSession) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(uc.uploadMetaPath(s.ID), data, 0o600)
+}
+
+// loadUploads restores the sessions left by the last run, the orphan part files are removed.
+func (uc *backupUseCase) loadUploads() error {
+	entries, err := os.ReadDir(uc.uploadPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		data, rErr := os.ReadFile(filepath.Join(uc.uploadPath, e.Name()))
+		if rErr != nil {
+			return rErr
+		}
+		s := &uploadSession{}
+		if err = json.Unmarshal(data, &s.session); err != nil || s.session.ID+".json" != e.Name() {
+			uc.logger.Warnf("Invalid upload session %s, removed", e.Name())
+			_ = os.Remove(filepath.Join(uc.uploadPath, e.Name()))
+			continue
+		}
+		info, sErr := os.Stat(uc.uploadPartPath(s.session.ID))
+		if sErr != nil {
+			_ = os.Remove(uc.uploadMetaPath(s.session.ID))
+			continue
+		}
+		s.session.Offset = min(info.Size(), s.session.Size)
+		uc.uploads[s.session.ID] = s
+	}
+	for _, e := range entries {
+		id := strings.TrimSuffix(e.Name(), ".part")
+		if filepath.Ext(e.Name()) == ".part" && uc.uploads[id] == nil {
+			_ = os.Remove(filepath.Join(uc.uploadPath, e.Name()))
+		}
+	}
+	uc.expireUploads()
+	return nil
+}
+
+// sanitizeFileName keeps the base name in letters, digits, dot, dash and underscore, only for display.
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	b := strings.Builder{}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	name = strings.TrimLeft(b.String(), ".")
+	if len(name) > maxFileNameLength {
+		name = name[len(name)-maxFileNameLength:]
+	}
+	if name == "" {
+		return "backup" + remoteArchiveExt
+	}
+	return name
+}
@@ -2,12 +2,20 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/chindada/capitan/internal/config"
+	"github.com/chindada/capitan/internal/usecases/entity"
 	"github.com/chindada/capitan/internal/usecases/repo"
 	"github.com/chindada/leopard/pkg/eventbus"
 	"github.com/chindada/leopard/pkg/log"
 	"github.com/chindada/panther/golang/pb"
+	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel/codes"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -15,37 +23,68 @@ import (
 
 type Basic interface {
 	GetAllStockDetail(ctx context.Context) (*pb.StockDetailList, error)
+	GetFutureDetail(ctx context.Context, filter *entity.ContractFilter) (*pb.FutureDetailList, error)
+	GetOptionDetail(ctx context.Context, filter *entity.ContractFilter) (*pb.OptionDetailList, error)
+	GetOptionChain(ctx context.Context, underlying, month string) (*entity.OptionChain, error)
+	SubscribeOptionChain(ctx context.Context, underlying, month string) (<-chan *entity.OptionChain, error)
+
+	Start()
+	TriggerRefresh() error
+	GetRefreshStatus() *entity.RefreshStatus
+	GetRefreshSummary() *entity.RefreshSummary
+
+	GetPriceSeries(ctx context.Context, kind entity.InstrumentKind, code string, from, to time.Time) (*entity.PriceSeries, error)
+
+	Close()
 }
 
+const optionChainPushInterval = 500 * time.Millisecond
+
 type basicUseCase struct {
 	basicRepo repo.BasicRepo
 
+	// ctx is the root context, the refresh stops on shutdown.
+	ctx    context.Context
 	logger *log.Log
 	bus    *eventbus.Bus
 
 	basicClient pb.BasicInterfaceClient
+	stream      Stream
+
+	scheduler      *cron.Cron
+	refreshEntry   cron.EntryID
+	retryTimes     int
+	retryInterval  time.Duration
+	refreshRunning sync.Mutex
+	statusLock     sync.RWMutex
+	status         entity.RefreshStatus
+	summaryLock    sync.RWMutex
+	summary        map[entity.InstrumentKind]*entity.InstrumentDiff
 }
 
-func NewBasic() Basic {
+func NewBasic(ctx context.Context, stream Stream) Basic {
 	cfg := config.Get()
 	pg := cfg.GetPostgresPool()
 	uc := &basicUseCase{
-		basicRepo:   repo.NewBasic(pg),
-		logger:      log.Get(),
-		bus:         eventbus.Get(),
-		basicClient: pb.NewBasicInterfaceClient(cfg.GetGRPCConn()),
+		basicRepo:     repo.NewBasic(pg),
+		ctx:           ctx,
+		logger:        log.Get(),
+		bus:           eventbus.Get(),
+		basicClient:   pb.NewBasicInterfaceClient(cfg.GetGRPCConn()),
+		stream:        stream,
+		scheduler:     cron.New(cron.WithLocation(cfg.Schedule.Location())),
+		retryTimes:    cfg.Schedule.RetryTimes,
+		retryInterval: cfg.Schedule.RetryInterval,
+		summary:       make(map[entity.InstrumentKind]*entity.InstrumentDiff),
 	}
 
-	routines := []func() error{
-		uc.updateStock,
-		uc.updateFuture,
-		uc.updateOption,
-	}
-	for _, routine := range routines {
-		if err := routine(); err != nil {
-			uc.logger.Fatalf("Failed to update data: %v", err)
-		}
+	entry, err := uc.scheduler.AddFunc(cfg.Schedule.BasicRefresh, uc.refreshWithRetry)
+	if err != nil {
+		uc.logger.Fatalf("Invalid SCHEDULE_BASIC_REFRESH %s: %v", cfg.Schedule.BasicRefresh, err)
 	}
+	uc.refreshEntry = entry
+	uc.scheduler.Start()
+	uc.bus.Subscribe(TopicUpstreamChanged, uc.onUpstreamChanged)
 	// uc.healthCheck()
 	return uc
 }
@@ -65,81 +104,609 @@ func NewBasic() Basic {
 // 	}()
 // }
 
+// onUpstreamChanged refreshes once reconnected if the last refresh failed or never ran.
+func (uc *basicUseCase) onUpstreamChanged(available bool) {
+	if !available {
+		return
+	}
+	status := uc.GetRefreshStatus()
+	if status.LastSuccess.IsZero() || status.LastError != "" {
+		go uc.refreshWithRetry()
+	}
+}
+
+// GetAllStockDetail returns the stocks of upstream, or the active ones in database if upstream unavailable.
 func (uc *basicUseCase) GetAllStockDetail(ctx context.Context) (*pb.StockDetailList, error) {
+	if !config.Get().UpstreamAvailable() {
+		list, err := uc.basicRepo.SelectActiveStockDetail(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.StockDetailList{List: list}, nil
+	}
 	return uc.basicClient.GetAllStockDetail(ctx, &emptypb.Empty{})
 }
 
-func (uc *basicUseCase) updateStock() error {
-	stocks, err := uc.GetAllStockDetail(context.Background())
+// GetFutureDetail returns the futures not yet expired from database.
+func (uc *basicUseCase) GetFutureDetail(ctx context.Context, filter *entity.ContractFilter) (*pb.FutureDetailList, error) {
+	list, err := uc.basicRepo.SelectFutureDetail(ctx, filter)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if len(stocks.GetList()) <= 1000 {
-		err = uc.basicRepo.InsertStockDetail(context.Background(), stocks.GetList())
-		if err != nil {
-			return err
+	return &pb.FutureDetailList{List: list}, nil
+}
+
+// GetOptionDetail returns the options not yet expired from database.
+func (uc *basicUseCase) GetOptionDetail(ctx context.Context, filter *entity.ContractFilter) (*pb.OptionDetailList, error) {
+	list, err := uc.basicRepo.SelectOptionDetail(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.OptionDetailList{List: list}, nil
+}
+
+// GetOptionChain returns the strike ladder of underlying and month, merged with live last price if any.
+func (uc *basicUseCase) GetOptionChain(ctx context.Context, underlying, month string) (*entity.OptionChain, error) {
+	list, err := uc.basicRepo.SelectOptionDetail(ctx, &entity.ContractFilter{
+		Category:      underlying,
+		DeliveryMonth: month,
+	})
+	if err != nil {
+		return nil, err
+	}
+	strikes := map[float64]*entity.OptionStrike{}
+	for _, v := range list {
+		strike, ok := strikes[v.GetStrikePrice()]
+		if !ok {
+			strike = &entity.OptionStrike{StrikePrice: v.GetStrikePrice()}
+			strikes[v.GetStrikePrice()] = strike
+		}
+		quote := &entity.OptionQuote{
+			Code:      v.GetCode(),
+			Reference: v.GetReference(),
+			LimitUp:   v.GetLimitUp(),
+			LimitDown: v.GetLimitDown(),
+		}
+		if tick := uc.stream.GetLastTick(v.GetCode()); tick != nil {
+			quote.LastPrice = tick.GetClose()
+			quote.LastTime = tick.GetDateTime()
+		}
+		switch v.GetOptionRight() {
+		case entity.OptionRightCall:
+			strike.Call = quote
+		case entity.OptionRightPut:
+			strike.Put = quote
 		}
 	}
-	spilts := [][]*pb.StockDetail{}
-	for i := 0; i < len(stocks.GetList()); i += 1000 {
-		end := min(i+1000, len(stocks.GetList()))
-		spilts = append(spilts, stocks.GetList()[i:end])
+	chain := &entity.OptionChain{
+		Underlying:    underlying,
+		DeliveryMonth: month,
+		Strikes:       make([]*entity.OptionStrike, 0, len(strikes)),
 	}
-	for _, split := range spilts {
-		err = uc.basicRepo.InsertStockDetail(context.Background(), split)
-		if err != nil {
-			return err
+	for _, strike := range strikes {
+		chain.Strikes = append(chain.Strikes, strike)
+	}
+	sort.Slice(chain.Strikes, func(i, j int) bool {
+		return chain.Strikes[i].StrikePrice < chain.Strikes[j].StrikePrice
+	})
+	return chain, nil
+}
+
+// SubscribeOptionChain sends the whole chain once, then again at most every optionChainPushInterval
+// if any last price changed. The channel is closed after ctx is done.
+// ErrUpstreamUnavailable is returned if no live price could be pushed.
+func (uc *basicUseCase) SubscribeOptionChain(ctx context.Context, underlying, month string) (<-chan *entity.OptionChain, error) {
+	if !config.Get().UpstreamAvailable() {
+		return nil, ErrUpstreamUnavailable
+	}
+	chain, err := uc.GetOptionChain(ctx, underlying, month)
+	if err != nil {
+		return nil, err
+	}
+	tickChan, cancel := uc.stream.SubscribeTick(chain.Codes())
+	result := make(chan *entity.OptionChain)
+	go func() {
+		defer close(result)
+		defer cancel()
+		ticker := time.NewTicker(optionChainPushInterval)
+		defer ticker.Stop()
+		changed := true
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case tick, ok := <-tickChan:
+				if !ok {
+					// the stream closed on shutdown
+					return
+				}
+				if chain.MergeLastPrice(tick.GetCode(), tick.GetClose(), tick.GetDateTime()) {
+					changed = true
+				}
+			case <-ticker.C:
+				if !changed {
+					continue
+				}
+				changed = false
+				select {
+				case result <- chain.Clone():
+				case <-ctx.Done():
+					return
+				}
+			}
 		}
+	}()
+	return result, nil
+}
+
+// GetPriceSeries returns the daily reference price of code of kind between from and to, both inclusive.
+func (uc *basicUseCase) GetPriceSeries(
+	ctx context.Context, kind entity.InstrumentKind, code string, from, to time.Time,
+) (*entity.PriceSeries, error) {
+	list, err := uc.basicRepo.SelectPriceHistory(ctx, kind, code, from, to)
+	if err != nil {
+		return nil, err
 	}
+	return &entity.PriceSeries{Code: code, Kind: kind, List: list}, nil
+}
+
+// TriggerRefresh starts a refresh with retry in background, returns ErrRefreshInProgress if one is running.
+func (uc *basicUseCase) TriggerRefresh() error {
+	if !config.Get().UpstreamAvailable() {
+		return ErrUpstreamUnavailable
+	}
+	if !uc.refreshRunning.TryLock() {
+		return ErrRefreshInProgress
+	}
+	go func() {
+		defer uc.refreshRunning.Unlock()
+		uc.retryRefresh()
+	}()
 	return nil
 }
 
-func (uc *basicUseCase) updateFuture() error {
-	futures, err := uc.basicClient.GetAllFutureDetail(context.Background(), &emptypb.Empty{})
+// Close stops the scheduler and waits the running refresh.
+// Start refreshes the data once, retried in background if failed, skipped if a refresh is running.
+func (uc *basicUseCase) Start() {
+	if !config.Get().UpstreamAvailable() {
+		uc.logger.Warn("Upstream unavailable, serve the data in database until reconnected")
+		return
+	}
+	if !uc.refreshRunning.TryLock() {
+		return
+	}
+	err := uc.refresh()
+	uc.refreshRunning.Unlock()
 	if err != nil {
-		return err
+		uc.logger.Errorf("Failed to update data: %v, retry in background", err)
+		go uc.refreshWithRetry()
 	}
-	if len(futures.GetList()) <= 1000 {
-		err = uc.basicRepo.InsertFutureDetail(context.Background(), futures.GetList())
-		if err != nil {
-			return err
+}
+
+func (uc *basicUseCase) Close() {
+	uc.bus.UnSubscribe(TopicUpstreamChanged, uc.onUpstreamChanged)
+	<-uc.scheduler.Stop().Done()
+	uc.refreshRunning.Lock()
+	defer uc.refreshRunning.Unlock()
+}
+
+func (uc *basicUseCase) GetRefreshStatus() *entity.RefreshStatus {
+	uc.statusLock.RLock()
+	status := uc.status
+	uc.statusLock.RUnlock()
+	status.NextRun = uc.scheduler.Entry(uc.refreshEntry).Next
+	return &status
+}
+
+// refreshWithRetry is skipped if another refresh is running.
+func (uc *basicUseCase) refreshWithRetry() {
+	if !uc.refreshRunning.TryLock() {
+		uc.logger.Warn("Refresh in progress, skip")
+		return
+	}
+	defer uc.refreshRunning.Unlock()
+	uc.retryRefresh()
+}
+
+func (uc *basicUseCase) retryRefresh() {
+	for i := 0; i <= uc.retryTimes; i++ {
+		if i > 0 {
+			uc.logger.Infof("Retry refresh in %s (%d/%d)", uc.retryInterval, i, uc.retryTimes)
+			select {
+			case <-uc.ctx.Done():
+				return
+			case <-time.After(uc.retryInterval):
+			}
 		}
+		err := uc.refresh()
+		if err == nil {
+			return
+		}
+		uc.logger.Errorf("Failed to update data: %v", err)
 	}
-	splits := [][]*pb.FutureDetail{}
-	for i := 0; i < len(futures.GetList()); i += 1000 {
-		end := min(i+1000, len(futures.GetList()))
-		splits = append(splits, futures.GetList()[i:end])
+}
+
+// refresh updates stocks, futures and options, records the result in status.
+func (uc *basicUseCase) refresh() error {
+	start := time.Now()
+	uc.setStatus(func(s *entity.RefreshStatus) {
+		s.Running = true
+		s.LastAttempt = start
+	})
+	ctx, span := tracer.Start(uc.ctx, "Basic.refresh")
+	defer span.End()
+	var err error
+	if !config.Get().UpstreamAvailable() {
+		err = ErrUpstreamUnavailable
+	} else {
+		for _, routine := range []func(context.Context) error{
+			uc.updateStock,
+			uc.updateFuture,
+			uc.updateOption,
+		} {
+			if rErr := routine(ctx); rErr != nil {
+				err = errors.Join(err, rErr)
+			}
+		}
 	}
-	for _, split := range splits {
-		err = uc.basicRepo.InsertFutureDetail(context.Background(), split)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	uc.setStatus(func(s *entity.RefreshStatus) {
+		s.Running = false
 		if err != nil {
-			return err
+			s.LastError = err.Error()
+			return
 		}
+		s.LastError = ""
+		s.LastSuccess = time.Now()
+	})
+	refreshDuration.WithLabelValues(result(err)).Observe(time.Since(start).Seconds())
+	if err == nil {
+		uc.logger.Info("Instrument master data refreshed")
+	}
+	return err
+}
+
+func (uc *basicUseCase) setStatus(fn func(s *entity.RefreshStatus)) {
+	uc.statusLock.Lock()
+	defer uc.statusLock.Unlock()
+	fn(&uc.status)
+}
+
+func (uc *basicUseCase) updateStock(ctx context.Context) error {
+	stocks, err := uc.basicClient.GetAllStockDetail(ctx, &emptypb.Empty{})
+	if err != nil {
+		return err
 	}
+	if len(stocks.GetList()) == 0 {
+		uc.logger.Warnf("Refresh %s returns nothing, skip import", entity.InstrumentKindStock)
+		return nil
+	}
+	before, err := uc.basicRepo.SelectActiveStockDetail(ctx)
+	if err != nil {
+		return err
+	}
+	rows, issues := stockRows(stocks.GetList())
+	imported := make(map[string]struct{}, len(rows))
+	for _, v := range rows {
+		imported[v.Code] = struct{}{}
+	}
+	diff := newInstrumentDiff(
+		entity.InstrumentKindStock,
+		signatures(before, stockSignature),
+		signatures(stocks.GetList(), stockSignature),
+		imported,
+		issues,
+	)
+	if err = uc.basicRepo.ImportStockDetail(ctx, rows, diff.Removed); err != nil {
+		return err
+	}
+	uc.publishDiff(diff, len(rows))
 	return nil
 }
 
-func (uc *basicUseCase) updateOption() error {
-	options, err := uc.basicClient.GetAllOptionDetail(context.Background(), &emptypb.Empty{})
+func (uc *basicUseCase) updateFuture(ctx context.Context) error {
+	futures, err := uc.basicClient.GetAllFutureDetail(ctx, &emptypb.Empty{})
 	if err != nil {
 		return err
 	}
-	if len(options.GetList()) <= 1000 {
-		err = uc.basicRepo.InsertOptionDetail(context.Background(), options.GetList())
-		if err != nil {
-			return err
+	if len(futures.GetList()) == 0 {
+		uc.logger.Warnf("Refresh %s returns nothing, skip import", entity.InstrumentKindFuture)
+		return nil
+	}
+	before, err := uc.basicRepo.SelectFutureDetail(ctx, &entity.ContractFilter{IncludeExpired: true})
+	if err != nil {
+		return err
+	}
+	rows, issues := futureRows(futures.GetList())
+	imported := make(map[string]struct{}, len(rows))
+	for _, v := range rows {
+		imported[v.Code] = struct{}{}
+	}
+	diff := newInstrumentDiff(
+		entity.InstrumentKindFuture,
+		signatures(before, futureSignature),
+		signatures(futures.GetList(), futureSignature),
+		imported,
+		issues,
+	)
+	if err = uc.basicRepo.ImportFutureDetail(ctx, rows, diff.Removed); err != nil {
+		return err
+	}
+	uc.publishDiff(diff, len(rows))
+	return nil
+}
+
+func (uc *basicUseCase) updateOption(ctx context.Context) error {
+	options, err := uc.basicClient.GetAllOptionDetail(ctx, &emptypb.Empty{})
+	if err != nil {
+		return err
+	}
+	if len(options.GetList()) == 0 {
+		uc.logger.Warnf("Refresh %s returns nothing, skip import", entity.InstrumentKindOption)
+		return nil
+	}
+	before, err := uc.basicRepo.SelectOptionDetail(ctx, &entity.ContractFilter{IncludeExpired: true})
+	if err != nil {
+		return err
+	}
+	rows, issues := optionRows(options.GetList())
+	imported := make(map[string]struct{}, len(rows))
+	for _, v := range rows {
+		imported[v.Code] = struct{}{}
+	}
+	diff := newInstrumentDiff(
+		entity.InstrumentKindOption,
+		signatures(before, optionSignature),
+		signatures(options.GetList(), optionSignature),
+		imported,
+		issues,
+	)
+	if err = uc.basicRepo.ImportOptionDetail(ctx, rows, diff.Removed); err != nil {
+		return err
+	}
+	uc.publishDiff(diff, len(rows))
+	return nil
+}
+
+// publishDiff keeps the diff as the latest summary of its kind, then publishes it.
+func (uc *basicUseCase) publishDiff(diff *entity.InstrumentDiff, imported int) {
+	refreshRows.WithLabelValues(string(diff.Kind), "imported").Set(float64(imported))
+	refreshRows.WithLabelValues(string(diff.Kind), "rejected").Set(float64(len(diff.Issues)))
+
+	uc.summaryLock.Lock()
+	uc.summary[diff.Kind] = diff
+	uc.summaryLock.Unlock()
+
+	if len(diff.Issues) > 0 {
+		uc.logger.Warnf("Refresh %s, %d rows rejected", diff.Kind, len(diff.Issues))
+	}
+	if !diff.IsEmpty() {
+		uc.logger.Infof("Refresh %s, added: %d, removed: %d, changed: %d",
+			diff.Kind, len(diff.Added), len(diff.Removed), len(diff.Changed))
+	}
+	uc.bus.PublishTopicEvent(TopicInstrumentDiff, diff)
+}
+
+// newInstrumentDiff compares the active rows before refresh with the upstream list.
+// Only imported codes count as added or changed, a rejected code is not removed either.
+func newInstrumentDiff(
+	kind entity.InstrumentKind,
+	before, after map[string]string,
+	imported map[string]struct{},
+	issues []*entity.ImportIssue,
+) *entity.InstrumentDiff {
+	diff := &entity.InstrumentDiff{
+		Kind:        kind,
+		Added:       []string{},
+		Removed:     []string{},
+		Changed:     []string{},
+		Issues:      issues,
+		RefreshedAt: time.Now(),
+	}
+	for code, sign := range after {
+		if _, ok := imported[code]; !ok {
+			continue
+		}
+		if old, ok := before[code]; !ok {
+			diff.Added = append(diff.Added, code)
+		} else if old != sign {
+			diff.Changed = append(diff.Changed, code)
 		}
 	}
-	splits := [][]*pb.OptionDetail{}
-	for i := 0; i < len(options.GetList()); i += 1000 {
-		end := min(i+1000, len(options.GetList()))
-		splits = append(splits, options.GetList()[i:end])
+	for code := range before {
+		if _, ok := after[code]; !ok {
+			diff.Removed = append(diff.Removed, code)
+		}
 	}
-	for _, split := range splits {
-		err = uc.basicRepo.InsertOptionDetail(context.Background(), split)
-		if err != nil {
-			return err
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+	return diff
+}
+
+func (uc *basicUseCase) GetRefreshSummary() *entity.RefreshSummary {
+	uc.summaryLock.RLock()
+	defer uc.summaryLock.RUnlock()
+	result := &entity.RefreshSummary{Diffs: []*entity.InstrumentDiff{}}
+	for _, kind := range []entity.InstrumentKind{
+		entity.InstrumentKindStock,
+		entity.InstrumentKindFuture,
+		entity.InstrumentKindOption,
+	} {
+		if diff, ok := uc.summary[kind]; ok {
+			result.Diffs = append(result.Diffs, diff)
 		}
 	}
-	return nil
+	return result
+}
+
+type codeDetail interface {
+	GetCode() string
+}
+
+func signatures[T codeDetail](list []T, fn func(T) string) map[string]string {
+	result := make(map[string]string, len(list))
+	for _, v := range list {
+		result[v.GetCode()] = fn(v)
+	}
+	return result
+}
+
+func stockSignature(v *pb.StockDetail) string {
+	return fmt.Sprintf("%s|%s|%s|%t",
+		v.GetName(), v.GetExchange(), v.GetCategory(), v.GetDayTrade() == entity.DayTradeYes)
+}
+
+func futureSignature(v *pb.FutureDetail) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s|%d",
+		v.GetSymbol(), v.GetName(), v.GetCategory(), v.GetDeliveryMonth(), v.GetDeliveryDate(),
+		v.GetUnderlyingKind(), v.GetUnit())
+}
+
+func optionSignature(v *pb.OptionDetail) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s|%d|%v|%s",
+		v.GetSymbol(), v.GetName(), v.GetCategory(), v.GetDeliveryMonth(), v.GetDeliveryDate(),
+		v.GetUnderlyingKind(), v.GetUnit(), v.GetStrikePrice(), v.GetOptionRight())
+}
+
+// rowValidator collects the issues of a refresh, the first row of a duplicated code wins.
+type rowValidator struct {
+	seen   map[string]struct{}
+	issues []*entity.ImportIssue
+}
+
+func newRowValidator(size int) *rowValidator {
+	return &rowValidator{
+		seen:   make(map[string]struct{}, size),
+		issues: []*entity.ImportIssue{},
+	}
+}
+
+func (v *rowValidator) reject(code, format string, args ...any) {
+	v.issues = append(v.issues, &entity.ImportIssue{Code: code, Reason: fmt.Sprintf(format, args...)})
+}
+
+// accept checks the code and marks it seen, false if the row should be skipped.
+func (v *rowValidator) accept(code string) bool {
+	if code == "" {
+		v.reject(code, "empty code")
+		return false
+	}
+	if _, ok := v.seen[code]; ok {
+		v.reject(code, "duplicate code")
+		return false
+	}
+	return true
+}
+
+func (v *rowValidator) parseDate(code, field, value string) (time.Time, bool) {
+	t, err := time.ParseInLocation(entity.ShortSlashTimeLayout, value, time.Local)
+	if err != nil {
+		v.reject(code, "invalid %s %q", field, value)
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func stockRows(list []*pb.StockDetail) ([]*entity.StockRow, []*entity.ImportIssue) {
+	v := newRowValidator(len(list))
+	rows := make([]*entity.StockRow, 0, len(list))
+	for _, item := range list {
+		if !v.accept(item.GetCode()) {
+			continue
+		}
+		updateDate, ok := v.parseDate(item.GetCode(), "update_date", item.GetUpdateDate())
+		if !ok {
+			continue
+		}
+		v.seen[item.GetCode()] = struct{}{}
+		rows = append(rows, &entity.StockRow{
+			Code:       item.GetCode(),
+			Name:       item.GetName(),
+			Exchange:   item.GetExchange(),
+			Category:   item.GetCategory(),
+			DayTrade:   item.GetDayTrade() == entity.DayTradeYes,
+			Reference:  item.GetReference(),
+			LimitUp:    item.GetLimitUp(),
+			LimitDown:  item.GetLimitDown(),
+			UpdateDate: updateDate,
+		})
+	}
+	return rows, v.issues
+}
+
+type contractDetail interface {
+	GetCode() string
+	GetSymbol() string
+	GetName() string
+	GetCategory() string
+	GetDeliveryMonth() string
+	GetDeliveryDate() string
+	GetUnderlyingKind() string
+	GetUnit() int64
+	GetLimitUp() float64
+	GetLimitDown() float64
+	GetReference() float64
+	GetUpdateDate() string
+}
+
+// contractRow validates the common fields of futures and options,
+// delivery date is moved to 13:30 which is the settlement time.
+func contractRow[T contractDetail](v *rowValidator, item T) (*entity.FutureRow, bool) {
+	if !v.accept(item.GetCode()) {
+		return nil, false
+	}
+	updateDate, ok := v.parseDate(item.GetCode(), "update_date", item.GetUpdateDate())
+	if !ok {
+		return nil, false
+	}
+	deliveryDate, ok := v.parseDate(item.GetCode(), "delivery_date", item.GetDeliveryDate())
+	if !ok {
+		return nil, false
+	}
+	v.seen[item.GetCode()] = struct{}{}
+	return &entity.FutureRow{
+		Code:           item.GetCode(),
+		Symbol:         item.GetSymbol(),
+		Name:           item.GetName(),
+		Category:       item.GetCategory(),
+		DeliveryMonth:  item.GetDeliveryMonth(),
+		DeliveryDate:   deliveryDate.Add(810 * time.Minute),
+		UnderlyingKind: item.GetUnderlyingKind(),
+		Unit:           item.GetUnit(),
+		LimitUp:        item.GetLimitUp(),
+		LimitDown:      item.GetLimitDown(),
+		Reference:      item.GetReference(),
+		UpdateDate:     updateDate,
+	}, true
+}
+
+func futureRows(list []*pb.FutureDetail) ([]*entity.FutureRow, []*entity.ImportIssue) {
+	v := newRowValidator(len(list))
+	rows := make([]*entity.FutureRow, 0, len(list))
+	for _, item := range list {
+		if row, ok := contractRow(v, item); ok {
+			rows = append(rows, row)
+		}
+	}
+	return rows, v.issues
+}
+
+func optionRows(list []*pb.OptionDetail) ([]*entity.OptionRow, []*entity.ImportIssue) {
+	v := newRowValidator(len(list))
+	rows := make([]*entity.OptionRow, 0, len(list))
+	for _, item := range list {
+		row, ok := contractRow(v, item)
+		if !ok {
+			continue
+		}
+		rows = append(rows, &entity.OptionRow{
+			FutureRow:   *row,
+			StrikePrice: item.GetStrikePrice(),
+			OptionRight: item.GetOptionRight(),
+		})
+	}
+	return rows, v.issues
 }
@@ -0,0 +1,206 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/chindada/capitan/internal/config"
+	"github.com/chindada/capitan/internal/usecases/entity"
+)
+
+//go:generate mockgen -source=usecase_health.go -destination=./mocks/mocks_usecase_health_test.go -package=mocks
+
+// pingTimeout bounds the postgres ping of a readiness check.
+const pingTimeout = 2 * time.Second
+
+// Runtime is the state of the app checked by Health, implemented by the app.
+type Runtime interface {
+	InMaintenance() bool
+	GetRefreshStatus() *entity.RefreshStatus
+}
+
+// Health serves the probes, not bound to database, so also answers in maintenance.
+type Health interface {
+	Live() *entity.Liveness
+	Ready(ctx context.Context) *entity.Readiness
+}
+
+type healthUseCase struct {
+	cfg *config.Config
+
+	runtime Runtime
+	stream  Stream
+
+	startedAt time.Time
+}
+
+func NewHealth(runtime Runtime, stream Stream) Health {
+	return &healthUseCase{
+		cfg:       config.Get(),
+		runtime:   runtime,
+		stream:    stream,
+		startedAt: time.Now(),
+	}
+}
+
+func (uc *healthUseCase) Live() *entity.Liveness {
+	return &entity.Liveness{
+		Status:    entity.HealthStatusUp,
+		StartedAt: uc.startedAt,
+	}
+}
+
+// Ready runs every check, the status is the worst of them.
+func (uc *healthUseCase) Ready(ctx context.Context) *entity.Readiness {
+	result := &entity.Readiness{
+		Status:    entity.HealthStatusUp,
+		CheckedAt: time.Now(),
+	}
+	for _, check := range []func(context.Context) *entity.ComponentHealth{
+		uc.checkMaintenance,
+		uc.checkPostgres,
+		uc.checkGRPC,
+		uc.checkRefresh,
+		uc.checkStream,
+		uc.checkDisk,
+	} {
+		c := check(ctx)
+		if c.Status == entity.HealthStatusDown ||
+			(c.Status == entity.HealthStatusDegraded && result.Status == entity.HealthStatusUp) {
+			result.Status = c.Status
+		}
+		result.Components = append(result.Components, c)
+	}
+	return result
+}
+
+func (uc *healthUseCase) checkMaintenance(context.Context) *entity.ComponentHealth {
+	c := &entity.ComponentHealth{Name: "maintenance", Status: entity.HealthStatusUp}
+	if uc.runtime.InMaintenance() {
+		c.Status = entity.HealthStatusDown
+		c.Detail = "draining for restore or shutdown"
+	}
+	return c
+}
+
+// checkPostgres is degraded if every connection of the pool is in use.
+func (uc *healthUseCase) checkPostgres(ctx context.Context) *entity.ComponentHealth {
+	c := &entity.ComponentHealth{Name: "postgres", Status: entity.HealthStatusUp}
+	pg := uc.cfg.LookupPostgresPool()
+	if pg == nil {
+		c.Status = entity.HealthStatusDown
+		c.Detail = "pool closed"
+		return c
+	}
+	stat := pg.Pool().Stat()
+	c.Metrics = map[string]any{
+		"total_conns":    stat.TotalConns(),
+		"acquired_conns": stat.AcquiredConns(),
+		"idle_conns":     stat.IdleConns(),
+		"max_conns":      stat.MaxConns(),
+	}
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	if err := pg.Pool().Ping(ctx); err != nil {
+		c.Status = entity.HealthStatusDown
+		c.Detail = err.Error()
+		return c
+	}
+	if stat.AcquiredConns() >= stat.MaxConns() {
+		c.Status = entity.HealthStatusDegraded
+		c.Detail = "pool exhausted"
+	}
+	return c
+}
+
+// checkGRPC is degraded if the upstream unavailable, the market features are off but the others still serve.
+func (uc *healthUseCase) checkGRPC(context.Context) *entity.ComponentHealth {
+	c := &entity.ComponentHealth{Name: "grpc", Status: entity.HealthStatusUp}
+	state := uc.cfg.GetGRPCConn().GetState()
+	c.Metrics = map[string]any{
+		"state":     state.String(),
+		"available": uc.cfg.UpstreamAvailable(),
+	}
+	if !uc.cfg.UpstreamAvailable() {
+		c.Status = entity.HealthStatusDegraded
+		c.Detail = fmt.Sprintf("upstream unavailable, connection %s", state)
+	}
+	return c
+}
+
+// checkRefresh is degraded if the last success is older than the max age, the data served may be stale.
+func (uc *healthUseCase) checkRefresh(context.Context) *entity.ComponentHealth {
+	c := &entity.ComponentHealth{Name: "instrument_refresh", Status: entity.HealthStatusUp}
+	status := uc.runtime.GetRefreshStatus()
+	c.Metrics = map[string]any{
+		"last_success": status.LastSuccess,
+		"last_error":   status.LastError,
+		"running":      status.Running,
+	}
+	switch {
+	case status.LastSuccess.IsZero():
+		c.Status = entity.HealthStatusDegraded
+		c.Detail = "never refreshed"
+	case time.Since(status.LastSuccess) > uc.cfg.Health.RefreshMaxAge:
+		c.Status = entity.HealthStatusDegraded
+		c.Detail = fmt.Sprintf("last refreshed %s ago", time.Since(status.LastSuccess).Round(time.Second))
+	}
+	return c
+}
+
+// checkStream is degraded if any default code is not streaming.
+func (uc *healthUseCase) checkStream(context.Context) *entity.ComponentHealth {
+	c := &entity.ComponentHealth{Name: "stream", Status: entity.HealthStatusUp}
+	subscriptions := uc.stream.GetSubscriptions()
+	inactive := []string{}
+	for _, s := range subscriptions {
+		if s.Default && !s.Active {
+			inactive = append(inactive, s.Code)
+		}
+	}
+	c.Metrics = map[string]any{"subscriptions": subscriptions}
+	if len(inactive) > 0 {
+		c.Status = entity.HealthStatusDegraded
+		c.Detail = fmt.Sprintf("not streaming: %v", inactive)
+	}
+	return c
+}
+
+// checkDisk is degraded if the free space of the backups is less than the min.
+func (uc *healthUseCase) checkDisk(context.Context) *entity.ComponentHealth {
+	c := &entity.ComponentHealth{Name: "backup_disk", Status: entity.HealthStatusUp}
+	fs := syscall.Statfs_t{}
+	if err := syscall.Statfs(existingDir(uc.cfg.Backup.Path), &fs); err != nil {
+		c.Status = entity.HealthStatusDegraded
+		c.Detail = err.Error()
+		return c
+	}
+	free := fs.Bavail * uint64(fs.Bsize)
+	c.Metrics = map[string]any{
+		"free_bytes": free,
+		"min_bytes":  uc.cfg.Health.MinFreeDisk,
+	}
+	if free < uc.cfg.Health.MinFreeDisk {
+		c.Status = entity.HealthStatusDegraded
+		c.Detail = "low disk space"
+	}
+	return c
+}
+
+// existingDir is path or the nearest parent existing, the backup path is not created until the first backup.
+func existingDir(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
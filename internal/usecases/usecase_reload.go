@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/chindada/capitan/internal/config"
+	"github.com/chindada/capitan/internal/usecases/entity"
+	"github.com/chindada/leopard/pkg/eventbus"
+	"github.com/chindada/leopard/pkg/log"
+)
+
+//go:generate mockgen -source=usecase_reload.go -destination=./mocks/mocks_usecase_reload_test.go -package=mocks
+
+// Reloader re-reads the config on SIGHUP or by admin, not bound to database.
+type Reloader interface {
+	ReloadConfig(ctx context.Context) (*entity.ConfigReload, error)
+}
+
+type reloadUseCase struct {
+	logger *log.Log
+	bus    *eventbus.Bus
+
+	mutex sync.Mutex
+}
+
+func NewReloader() Reloader {
+	return &reloadUseCase{
+		logger: log.Get(),
+		bus:    eventbus.Get(),
+	}
+}
+
+// ReloadConfig applies the live keys, the subscribers of TopicConfigReloaded are done before return.
+func (uc *reloadUseCase) ReloadConfig(_ context.Context) (*entity.ConfigReload, error) {
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+	applied, restart, err := config.Get().Reload()
+	if errors.Is(err, config.ErrInvalid) {
+		uc.logger.Warnf("Reload config: %v", err)
+		return nil, ErrConfigInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := &entity.ConfigReload{
+		Applied:         append([]string{}, applied...),
+		RestartRequired: append([]string{}, restart...),
+	}
+	if len(applied) > 0 {
+		uc.bus.PublishTopicEvent(TopicConfigReloaded, result)
+	}
+	return result, nil
+}
@@ -0,0 +1,287 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/chindada/capitan/internal/config"
+	"github.com/chindada/capitan/internal/usecases/entity"
+	"github.com/chindada/capitan/internal/usecases/repo"
+	"github.com/chindada/leopard/pkg/eventbus"
+	"github.com/chindada/leopard/pkg/log"
+	"github.com/chindada/panther/golang/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+//go:generate mockgen -source=usecase_setting.go -destination=./mocks/mocks_usecase_setting_test.go -package=mocks
+
+// Settings reads and writes the known keys of system_setting and capitan_setting by name, the secrets are never returned.
+type Settings interface {
+	ListSettings(ctx context.Context) ([]*entity.Setting, error)
+	GetSetting(ctx context.Context, name string) (*entity.Setting, error)
+	UpdateSetting(ctx context.Context, name string, value json.RawMessage) (*entity.Setting, error)
+	Close()
+}
+
+// settingDefinition is a known key of panther, or local of capitan, newValue returns the default to decode into.
+type settingDefinition struct {
+	key      pb.SettingKey
+	local    entity.LocalSettingKey
+	name     string
+	readOnly bool
+	newValue func() any
+	// decode is json if nil, update always takes json.
+	decode   func(data []byte, v any) error
+	validate func(v any) error
+	// redact clears the secrets, keepSecrets fills the empty secrets of v from saved.
+	redact      func(v any)
+	keepSecrets func(v, saved any)
+}
+
+// settingRegistry is all keys served, the backup scheduled names are state, not setting.
+var settingRegistry = []*settingDefinition{
+	{
+		key:      pb.SettingKey_SETTING_JWT,
+		name:     "jwt",
+		readOnly: true,
+		newValue: func() any { return &entity.JWTSetting{} },
+		decode:   decodeJWTSetting,
+		redact:   func(v any) { v.(*entity.JWTSetting).Secret = "" },
+	},
+	{
+		local: settingKeyBackupSchedule,
+		name:  "backup_schedule",
+		newValue: func() any {
+			s := defaultBackupSchedule
+			return &s
+		},
+		validate: func(v any) error { return validateSchedule(v.(*entity.BackupSchedule)) },
+	},
+	{
+		local:    settingKeyBackupTargets,
+		name:     "backup_targets",
+		newValue: func() any { return &[]*entity.BackupTarget{} },
+		validate: func(v any) error { return validateTargets(*v.(*[]*entity.BackupTarget)) },
+		redact:   func(v any) { redactTargets(*v.(*[]*entity.BackupTarget)) },
+		keepSecrets: func(v, saved any) {
+			keepTargetSecrets(*v.(*[]*entity.BackupTarget), *saved.(*[]*entity.BackupTarget))
+		},
+	},
+}
+
+func decodeJWTSetting(data []byte, v any) error {
+	s := &pb.SystemSetting{}
+	if err := proto.Unmarshal(data, s); err != nil {
+		return err
+	}
+	jwt := v.(*entity.JWTSetting)
+	jwt.Secret = s.GetJwt().GetSecret()
+	if s.GetJwt().GetUpdatedAt() != nil {
+		jwt.UpdatedAt = s.GetJwt().GetUpdatedAt().AsTime().Local()
+	}
+	return nil
+}
+
+func findSetting(name string) *settingDefinition {
+	for _, v := range settingRegistry {
+		if v.name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+type settingUseCase struct {
+	systemRepo repo.SystemRepo
+
+	logger *log.Log
+	bus    *eventbus.Bus
+
+	// cache is the content of the names read, nil if not stored, dropped on TopicSettingChanged and TopicLocalSettingChanged.
+	cacheLock sync.RWMutex
+	cache     map[string][]byte
+	version   uint64
+}
+
+func NewSettings() Settings {
+	uc := &settingUseCase{
+		systemRepo: repo.NewSystemRepo(config.Get().GetPostgresPool()),
+		logger:     log.Get(),
+		bus:        eventbus.Get(),
+		cache:      make(map[string][]byte),
+	}
+	uc.bus.Subscribe(TopicSettingChanged, uc.invalidate)
+	uc.bus.Subscribe(TopicLocalSettingChanged, uc.invalidateLocal)
+	return uc
+}
+
+func (uc *settingUseCase) Close() {
+	uc.bus.UnSubscribe(TopicSettingChanged, uc.invalidate)
+	uc.bus.UnSubscribe(TopicLocalSettingChanged, uc.invalidateLocal)
+}
+
+func (uc *settingUseCase) invalidate(key pb.SettingKey) {
+	uc.drop(func(def *settingDefinition) bool { return def.local == "" && def.key == key })
+}
+
+func (uc *settingUseCase) invalidateLocal(key entity.LocalSettingKey) {
+	uc.drop(func(def *settingDefinition) bool { return def.local == key })
+}
+
+func (uc *settingUseCase) drop(match func(def *settingDefinition) bool) {
+	uc.cacheLock.Lock()
+	defer uc.cacheLock.Unlock()
+	for _, def := range settingRegistry {
+		if match(def) {
+			delete(uc.cache, def.name)
+		}
+	}
+	uc.version++
+}
+
+// load reads through the cache, a read racing with a change is not cached.
+func (uc *settingUseCase) load(ctx context.Context, def *settingDefinition) ([]byte, error) {
+	uc.cacheLock.RLock()
+	data, ok := uc.cache[def.name]
+	version := uc.version
+	uc.cacheLock.RUnlock()
+	if ok {
+		return data, nil
+	}
+	var err error
+	if def.local != "" {
+		data, err = uc.systemRepo.SelectLocalSetting(ctx, def.local)
+	} else {
+		data, err = uc.systemRepo.SelectRawSetting(ctx, def.key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	uc.cacheLock.Lock()
+	if uc.version == version {
+		uc.cache[def.name] = data
+	}
+	uc.cacheLock.Unlock()
+	return data, nil
+}
+
+func (uc *settingUseCase) decode(def *settingDefinition, data []byte) (any, error) {
+	v := def.newValue()
+	if data == nil {
+		return v, nil
+	}
+	decode := json.Unmarshal
+	if def.decode != nil {
+		decode = def.decode
+	}
+	if err := decode(data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (uc *settingUseCase) view(def *settingDefinition, data []byte) (*entity.Setting, error) {
+	v, err := uc.decode(def, data)
+	if err != nil {
+		return nil, err
+	}
+	if def.redact != nil {
+		def.redact(v)
+	}
+	return &entity.Setting{
+		Name:     def.name,
+		Key:      int32(def.key),
+		ReadOnly: def.readOnly,
+		Stored:   data != nil,
+		Value:    v,
+	}, nil
+}
+
+func (uc *settingUseCase) ListSettings(ctx context.Context) ([]*entity.Setting, error) {
+	result := make([]*entity.Setting, 0, len(settingRegistry))
+	for _, def := range settingRegistry {
+		data, err := uc.load(ctx, def)
+		if err != nil {
+			return nil, err
+		}
+		s, err := uc.view(def, data)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func (uc *settingUseCase) GetSetting(ctx context.Context, name string) (*entity.Setting, error) {
+	def := findSetting(name)
+	if def == nil {
+		return nil, ErrSettingNotFound
+	}
+	data, err := uc.load(ctx, def)
+	if err != nil {
+		return nil, err
+	}
+	return uc.view(def, data)
+}
+
+// UpdateSetting replaces the whole value, the missing fields take the default and the empty secrets are kept.
+func (uc *settingUseCase) UpdateSetting(ctx context.Context, name string, value json.RawMessage) (*entity.Setting, error) {
+	def := findSetting(name)
+	if def == nil {
+		return nil, ErrSettingNotFound
+	}
+	if def.readOnly {
+		return nil, ErrSettingReadOnly
+	}
+	v := def.newValue()
+	dec := json.NewDecoder(bytes.NewReader(value))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		uc.logger.Warnf("Setting %s: %v", name, err)
+		return nil, ErrSettingInvalid
+	}
+	if def.keepSecrets != nil {
+		data, err := uc.load(ctx, def)
+		if err != nil {
+			return nil, err
+		}
+		saved, err := uc.decode(def, data)
+		if err != nil {
+			return nil, err
+		}
+		def.keepSecrets(v, saved)
+	}
+	if def.validate != nil {
+		if err := def.validate(v); err != nil {
+			uc.logger.Warnf("Setting %s: %v", name, err)
+			return nil, ErrSettingInvalid
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err = uc.store(ctx, def, data); err != nil {
+		return nil, err
+	}
+	return uc.view(def, data)
+}
+
+// store writes data of def, then tells the others by the topic of the key.
+func (uc *settingUseCase) store(ctx context.Context, def *settingDefinition, data []byte) error {
+	if def.local != "" {
+		if err := uc.systemRepo.UpsertLocalSetting(ctx, def.local, data); err != nil {
+			return err
+		}
+		uc.bus.PublishTopicEvent(TopicLocalSettingChanged, def.local)
+		return nil
+	}
+	if err := uc.systemRepo.UpsertRawSetting(ctx, def.key, data); err != nil {
+		return err
+	}
+	uc.bus.PublishTopicEvent(TopicSettingChanged, def.key)
+	return nil
+}
@@ -2,10 +2,13 @@ package usecases
 
 import (
 	"context"
-	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/chindada/capitan/internal/config"
+	"github.com/chindada/capitan/internal/usecases/entity"
 	"github.com/chindada/leopard/pkg/eventbus"
 	"github.com/chindada/leopard/pkg/log"
 	"github.com/chindada/panther/golang/pb"
@@ -15,64 +18,314 @@ import (
 
 //go:generate mockgen -source=usecase_stream.go -destination=./mocks/mocks_usecase_stream_test.go -package=mocks
 
-type Stream interface{}
+const tickSubscriberBuffer = 256
+
+type Stream interface {
+	GetLastTick(code string) *pb.FutureTick
+	SubscribeTick(codes []string) (<-chan *pb.FutureTick, func())
+	GetSubscriptions() []*entity.TickSubscription
+	Close()
+}
 
 type streamUseCase struct {
+	// ctx is the root context, the grpc streams are cancelled on shutdown.
+	ctx    context.Context
 	logger *log.Log
 	bus    *eventbus.Bus
 
 	streamClient pb.StreamInterfaceClient
+
+	mutex       sync.RWMutex
+	lastTicks   map[string]*pb.FutureTick
+	subscribed  map[string]*tickStream
+	subscribers map[int64]*tickSubscriber
+	nextID      int64
+	// defaults are the codes of config subscribed without any client.
+	defaults map[string]struct{}
+	closed   bool
+
+	eventRunning sync.Mutex
+}
+
+// tickStream is the running subscription of a code, stopped by cancel.
+type tickStream struct {
+	cancel context.CancelFunc
+}
+
+type tickSubscriber struct {
+	codes map[string]struct{}
+	ch    chan *pb.FutureTick
 }
 
-func NewStream() Stream {
+func NewStream(ctx context.Context) Stream {
 	cfg := config.Get()
 	uc := &streamUseCase{
+		ctx:          ctx,
 		logger:       log.Get(),
 		bus:          eventbus.Get(),
 		streamClient: pb.NewStreamInterfaceClient(cfg.GetGRPCConn()),
+		lastTicks:    make(map[string]*pb.FutureTick),
+		subscribed:   make(map[string]*tickStream),
+		subscribers:  make(map[int64]*tickSubscriber),
+		defaults:     make(map[string]struct{}),
 	}
 	go uc.subscribeShioajiEvent()
-	codes := []string{"TXFG5", "MXFG5", "TMFG5"}
+	uc.setDefaults(cfg.GetStreamCodes())
+	uc.bus.Subscribe(TopicConfigReloaded, uc.onConfigReloaded)
+	uc.bus.Subscribe(TopicUpstreamChanged, uc.onUpstreamChanged)
+	return uc
+}
+
+// onUpstreamChanged subscribes again the codes of config and clients, the streams lost are gone already.
+func (uc *streamUseCase) onUpstreamChanged(available bool) {
+	if !available {
+		return
+	}
+	go uc.subscribeShioajiEvent()
+	uc.mutex.RLock()
+	codes := make([]string, 0, len(uc.defaults))
+	for code := range uc.defaults {
+		codes = append(codes, code)
+	}
+	for _, sub := range uc.subscribers {
+		for code := range sub.codes {
+			codes = append(codes, code)
+		}
+	}
+	uc.mutex.RUnlock()
 	for _, code := range codes {
-		go func(c string) {
-			_ = uc.subscribeFutureTick(c)
-		}(code)
+		uc.ensureSubscribed(code)
+	}
+}
+
+func (uc *streamUseCase) onConfigReloaded(reload *entity.ConfigReload) {
+	if slices.Contains(reload.Applied, "stream.codes") {
+		uc.setDefaults(config.Get().GetStreamCodes())
+	}
+}
+
+// setDefaults subscribes the new codes, the codes removed are stopped unless a client still wants them.
+func (uc *streamUseCase) setDefaults(codes []string) {
+	defaults := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		defaults[code] = struct{}{}
+	}
+	uc.mutex.Lock()
+	removed := []string{}
+	for code := range uc.defaults {
+		if _, ok := defaults[code]; !ok && !uc.wanted(code) {
+			removed = append(removed, code)
+		}
+	}
+	uc.defaults = defaults
+	for _, code := range removed {
+		uc.stopStream(code)
+	}
+	uc.mutex.Unlock()
+
+	for _, code := range codes {
+		uc.ensureSubscribed(code)
+	}
+}
+
+// wanted reports if any client subscribes code, must hold the mutex.
+func (uc *streamUseCase) wanted(code string) bool {
+	for _, sub := range uc.subscribers {
+		if _, ok := sub.codes[code]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// stopStream cancels the stream of code if running, must hold the mutex.
+func (uc *streamUseCase) stopStream(code string) {
+	if s, ok := uc.subscribed[code]; ok {
+		s.cancel()
+		delete(uc.subscribed, code)
 	}
-	return uc
 }
 
+// GetLastTick returns nil if no tick of code received yet.
+func (uc *streamUseCase) GetLastTick(code string) *pb.FutureTick {
+	uc.mutex.RLock()
+	defer uc.mutex.RUnlock()
+	return uc.lastTicks[code]
+}
+
+// GetSubscriptions returns the default codes and the codes streaming, sorted by code.
+func (uc *streamUseCase) GetSubscriptions() []*entity.TickSubscription {
+	uc.mutex.RLock()
+	defer uc.mutex.RUnlock()
+	codes := make(map[string]struct{}, len(uc.defaults)+len(uc.subscribed))
+	for code := range uc.defaults {
+		codes[code] = struct{}{}
+	}
+	for code := range uc.subscribed {
+		codes[code] = struct{}{}
+	}
+	result := make([]*entity.TickSubscription, 0, len(codes))
+	for code := range codes {
+		_, isDefault := uc.defaults[code]
+		_, active := uc.subscribed[code]
+		result = append(result, &entity.TickSubscription{
+			Code:     code,
+			Default:  isDefault,
+			Active:   active,
+			LastTick: uc.lastTicks[code].GetDateTime(),
+		})
+	}
+	slices.SortFunc(result, func(a, b *entity.TickSubscription) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+	return result
+}
+
+// SubscribeTick subscribes the ticks of codes, options share the same tick stream with futures.
+// The returned func must be called to release the subscription, the streams no longer wanted are stopped.
+// Ticks are dropped if the receiver is too slow.
+func (uc *streamUseCase) SubscribeTick(codes []string) (<-chan *pb.FutureTick, func()) {
+	sub := &tickSubscriber{
+		codes: make(map[string]struct{}, len(codes)),
+		ch:    make(chan *pb.FutureTick, tickSubscriberBuffer),
+	}
+	for _, code := range codes {
+		sub.codes[code] = struct{}{}
+	}
+
+	uc.mutex.Lock()
+	if uc.closed {
+		uc.mutex.Unlock()
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+	uc.nextID++
+	id := uc.nextID
+	uc.subscribers[id] = sub
+	uc.mutex.Unlock()
+
+	for _, code := range codes {
+		uc.ensureSubscribed(code)
+	}
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			uc.mutex.Lock()
+			defer uc.mutex.Unlock()
+			delete(uc.subscribers, id)
+			// the codes of no client left and not default are stopped
+			for code := range sub.codes {
+				if _, isDefault := uc.defaults[code]; !isDefault && !uc.wanted(code) {
+					uc.stopStream(code)
+				}
+			}
+		})
+	}
+}
+
+// Close stops all the tick streams and closes the channels of the subscribers,
+// the ticks buffered are still received before the channel closed.
+func (uc *streamUseCase) Close() {
+	uc.bus.UnSubscribe(TopicConfigReloaded, uc.onConfigReloaded)
+	uc.bus.UnSubscribe(TopicUpstreamChanged, uc.onUpstreamChanged)
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+	uc.closed = true
+	for code, s := range uc.subscribed {
+		s.cancel()
+		delete(uc.subscribed, code)
+	}
+	for id, sub := range uc.subscribers {
+		close(sub.ch)
+		delete(uc.subscribers, id)
+	}
+}
+
+func (uc *streamUseCase) ensureSubscribed(code string) {
+	uc.mutex.Lock()
+	if _, ok := uc.subscribed[code]; ok || uc.closed {
+		uc.mutex.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(uc.ctx)
+	s := &tickStream{cancel: cancel}
+	uc.subscribed[code] = s
+	uc.mutex.Unlock()
+
+	go func() {
+		defer cancel()
+		if err := uc.subscribeFutureTick(ctx, code); err != nil && ctx.Err() == nil {
+			uc.logger.Warnf("Subscribe tick %s stopped: %v", code, err)
+		}
+		uc.mutex.Lock()
+		if uc.subscribed[code] == s {
+			delete(uc.subscribed, code)
+		}
+		uc.mutex.Unlock()
+	}()
+}
+
+func (uc *streamUseCase) dispatchTick(tick *pb.FutureTick) {
+	uc.mutex.Lock()
+	defer uc.mutex.Unlock()
+	uc.lastTicks[tick.GetCode()] = tick
+	for _, sub := range uc.subscribers {
+		if _, ok := sub.codes[tick.GetCode()]; !ok {
+			continue
+		}
+		select {
+		case sub.ch <- tick:
+		default:
+		}
+	}
+}
+
+// subscribeShioajiEvent logs the events until the upstream lost, skipped if running.
 func (uc *streamUseCase) subscribeShioajiEvent() {
-	eventStream, err := uc.streamClient.SubscribeShioajiEvent(context.Background(), &emptypb.Empty{})
+	if !uc.eventRunning.TryLock() {
+		return
+	}
+	defer uc.eventRunning.Unlock()
+	eventStream, err := uc.streamClient.SubscribeShioajiEvent(uc.ctx, &emptypb.Empty{})
 	if err != nil {
 		s := status.Convert(err)
-		uc.logger.Fatalf("Error(%d): %s", s.Code(), s.Message())
+		uc.logger.Warnf("Subscribe event error(%d): %s", s.Code(), s.Message())
+		return
 	}
 	for {
 		event, rErr := eventStream.Recv()
+		if rErr != nil && uc.ctx.Err() != nil {
+			return
+		}
 		if rErr != nil {
 			s := status.Convert(rErr)
-			uc.logger.Fatalf("Error(%d): %s", s.Code(), s.Message())
+			uc.logger.Warnf("Subscribe event stopped(%d): %s", s.Code(), s.Message())
+			return
 		}
 		uc.logger.Warnf("Resp code: %d, Event code: %d, Info: %s, Event: %s",
 			event.GetRespCode(), event.GetEventCode(), event.GetInfo(), event.GetEvent())
 	}
 }
 
-func (uc *streamUseCase) subscribeFutureTick(code string) error {
-	tickStream, err := uc.streamClient.SubscribeFutureTick(context.Background(), &pb.SubscribeFutureRequest{
+func (uc *streamUseCase) subscribeFutureTick(ctx context.Context, code string) error {
+	stream, err := uc.streamClient.SubscribeFutureTick(ctx, &pb.SubscribeFutureRequest{
 		Code: code,
 	})
 	if err != nil {
 		return err
 	}
 	for {
-		tick, rErr := tickStream.Recv()
+		tick, rErr := stream.Recv()
 		if rErr != nil {
 			return rErr
 		}
 		tickTime, _ := time.ParseInLocation(time.DateTime, tick.GetDateTime(), time.Local)
-		fmt.Printf("Received tick: %s, price: %f, volume: %d, time: %v,time gap: %d\n",
-			tick.GetCode(), tick.GetClose(), tick.GetVolume(), tickTime, time.Since(tickTime).Milliseconds())
+		gap := time.Since(tickTime)
+		ticksReceived.WithLabelValues(code).Inc()
+		tickLag.WithLabelValues(code).Observe(gap.Seconds())
+		uc.logger.Debugf("Received tick: %s, price: %f, volume: %d, time: %v,time gap: %d",
+			tick.GetCode(), tick.GetClose(), tick.GetVolume(), tickTime, gap.Milliseconds())
+		uc.dispatchTick(tick)
 	}
 }
 
@@ -2,6 +2,7 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/mail"
 
@@ -57,7 +58,7 @@ type systemUseCase struct {
 	bus    *eventbus.Bus
 }
 
-func NewSystem() System {
+func NewSystem(ctx context.Context) System {
 	logger := log.Get()
 	cfg := config.Get()
 	pg := cfg.GetPostgresPool()
@@ -67,12 +68,12 @@ func NewSystem() System {
 		logger:     logger,
 		bus:        eventbus.Get(),
 	}
-	uc.initUsers()
+	uc.initUsers(ctx)
 	return uc
 }
 
-func (uc *systemUseCase) initUsers() {
-	all, err := uc.userRepo.SelectAllUser(context.Background())
+func (uc *systemUseCase) initUsers(ctx context.Context) {
+	all, err := uc.userRepo.SelectAllUser(ctx)
 	if err != nil {
 		uc.logger.Fatal(err)
 		return
@@ -93,7 +94,7 @@ func (uc *systemUseCase) initUsers() {
 		uc.logger.Infof("No user found, creating %s user: %s",
 			defaultUserRoot,
 			root.GetBasic().GetPassword())
-		if err = uc.CreateUser(context.Background(), root); err != nil {
+		if err = uc.CreateUser(ctx, root); err != nil {
 			uc.logger.Fatal(err)
 		}
 	}
@@ -144,19 +145,29 @@ func (uc *systemUseCase) GetLastJWT(ctx context.Context) (string, error) {
 }
 
 func (uc *systemUseCase) InsertJWT(ctx context.Context, jwt string) error {
-	return uc.systemRepo.InsertSetting(ctx, &pb.SystemSetting{
+	if err := uc.systemRepo.UpsertSetting(ctx, &pb.SystemSetting{
 		Key: pb.SettingKey_SETTING_JWT,
 		Value: &pb.SystemSetting_Jwt{
 			Jwt: &pb.SettingJWT{
-				Secret: jwt,
+				Secret:    jwt,
+				UpdatedAt: timestamppb.Now(),
 			},
 		},
-	})
+	}); err != nil {
+		return err
+	}
+	uc.bus.PublishTopicEvent(TopicSettingChanged, pb.SettingKey_SETTING_JWT)
+	return nil
 }
 
-func (uc *systemUseCase) Login(ctx *gin.Context, loginReq *pb.LoginRequest) (*pb.User, error) {
-	var err error
+func (uc *systemUseCase) Login(ctx *gin.Context, loginReq *pb.LoginRequest) (_ *pb.User, err error) {
 	var code pb.LoginRespCode
+	defer func() {
+		// asked for the mfa code, counted once the code is given
+		if !errors.Is(err, ErrMfaCodeRequired) {
+			logins.WithLabelValues(code.String()).Inc()
+		}
+	}()
 	user, err := uc.userRepo.SelectUserByUsername(ctx, loginReq.GetUsername())
 	if err != nil {
 		code = pb.LoginRespCode_DB_ERROR
@@ -0,0 +1,542 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"sort"
+	"time"
+
+	"github.com/chindada/capitan/internal/config"
+	"github.com/chindada/capitan/internal/usecases/entity"
+	"github.com/chindada/capitan/internal/usecases/modules/transfer"
+	"github.com/chindada/capitan/internal/usecases/repo"
+	"github.com/chindada/capitan/internal/version"
+	"github.com/chindada/leopard/pkg/eventbus"
+	"github.com/chindada/leopard/pkg/log"
+	"github.com/chindada/panther/golang/pb"
+	"golang.org/x/crypto/bcrypt"
+)
+
+//go:generate mockgen -source=usecase_transfer.go -destination=./mocks/mocks_usecase_transfer_test.go -package=mocks
+
+// Transfer exports and imports one domain between instances, there is no watchlist in capitan to transfer.
+// Users carry the password hash but not the totp, settings exclude the jwt secret and the backup state.
+type Transfer interface {
+	Export(ctx context.Context, domain entity.TransferDomain, format entity.TransferFormat) (*TransferExport, error)
+	Import(
+		ctx context.Context, domain entity.TransferDomain, format entity.TransferFormat,
+		src io.Reader, policy entity.ConflictPolicy,
+	) (*entity.TransferResult, error)
+}
+
+type transferUseCase struct {
+	userRepo   repo.UserRepo
+	systemRepo repo.SystemRepo
+	basicRepo  repo.BasicRepo
+	backupRepo repo.BackupRepo
+
+	logger *log.Log
+	bus    *eventbus.Bus
+}
+
+func NewTransfer() Transfer {
+	pg := config.Get().GetPostgresPool()
+	return &transferUseCase{
+		userRepo:   repo.NewUserRepo(pg),
+		systemRepo: repo.NewSystemRepo(pg),
+		basicRepo:  repo.NewBasic(pg),
+		backupRepo: repo.NewBackupRepo(pg),
+		logger:     log.Get(),
+		bus:        eventbus.Get(),
+	}
+}
+
+// TransferExport is the records of a domain loaded, written as a file by WriteTo.
+type TransferExport struct {
+	FileName    string
+	ContentType string
+
+	format  entity.TransferFormat
+	header  *entity.TransferHeader
+	records []any
+}
+
+func (e *TransferExport) WriteTo(w io.Writer) (int64, error) {
+	counter := &countingWriter{w: w}
+	tw, err := transfer.NewWriter(counter, e.format, e.header)
+	if err != nil {
+		return counter.n, err
+	}
+	for _, v := range e.records {
+		if err = tw.Write(v); err != nil {
+			return counter.n, err
+		}
+	}
+	err = tw.Close()
+	return counter.n, err
+}
+
+// transferableSetting is true for the known keys of capitan_setting except the secrets and the state of this instance.
+func transferableSetting(key entity.LocalSettingKey) bool {
+	return key == settingKeyBackupSchedule
+}
+
+// Export loads all records of domain sorted by key, nothing is written before the records are loaded.
+func (uc *transferUseCase) Export(ctx context.Context, domain entity.TransferDomain, format entity.TransferFormat) (*TransferExport, error) {
+	if !transfer.Valid(format) {
+		return nil, ErrTransferFormatInvalid
+	}
+	var records []any
+	var err error
+	switch domain {
+	case entity.TransferDomainUsers:
+		records, err = uc.exportUsers(ctx)
+	case entity.TransferDomainSettings:
+		if format == entity.TransferFormatProtobuf {
+			return nil, ErrTransferFormatInvalid
+		}
+		records, err = uc.exportSettings(ctx)
+	case entity.TransferDomainStocks:
+		records, err = uc.exportStocks(ctx)
+	case entity.TransferDomainFutures:
+		records, err = uc.exportFutures(ctx)
+	case entity.TransferDomainOptions:
+		records, err = uc.exportOptions(ctx)
+	default:
+		return nil, ErrTransferDomainInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	core, local, err := uc.backupRepo.SelectSchemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &TransferExport{
+		FileName:    fmt.Sprintf("capitan_%s_%s%s", domain, now.Format("20060102150405"), transfer.Ext(format)),
+		ContentType: transfer.ContentType(format),
+		format:      format,
+		header: &entity.TransferHeader{
+			Domain:         domain,
+			AppVersion:     version.GetCore().GetVersion(),
+			Migration:      core,
+			LocalMigration: local,
+			Count:          len(records),
+			CreatedAt:      now,
+		},
+		records: records,
+	}, nil
+}
+
+func (uc *transferUseCase) exportUsers(ctx context.Context) ([]any, error) {
+	users, err := uc.userRepo.SelectAllUserCredential(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]any, 0, len(users))
+	for _, v := range users {
+		records = append(records, &pb.User{Basic: v.GetBasic()})
+	}
+	return records, nil
+}
+
+func (uc *transferUseCase) exportSettings(ctx context.Context) ([]any, error) {
+	settings, err := uc.systemRepo.SelectAllLocalSetting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]entity.LocalSettingKey, 0, len(settings))
+	for k := range settings {
+		if transferableSetting(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	records := make([]any, 0, len(keys))
+	for _, k := range keys {
+		record := &entity.SettingRecord{Key: string(k)}
+		if data := settings[k]; len(data) > 0 && json.Valid(data) {
+			record.Value = data
+		} else {
+			record.Raw = settings[k]
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (uc *transferUseCase) exportStocks(ctx context.Context) ([]any, error) {
+	list, err := uc.basicRepo.SelectActiveStockDetail(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sortedRecords(list, (*pb.StockDetail).GetCode), nil
+}
+
+func (uc *transferUseCase) exportFutures(ctx context.Context) ([]any, error) {
+	list, err := uc.basicRepo.SelectFutureDetail(ctx, &entity.ContractFilter{IncludeExpired: true})
+	if err != nil {
+		return nil, err
+	}
+	return sortedRecords(list, (*pb.FutureDetail).GetCode), nil
+}
+
+func (uc *transferUseCase) exportOptions(ctx context.Context) ([]any, error) {
+	list, err := uc.basicRepo.SelectOptionDetail(ctx, &entity.ContractFilter{IncludeExpired: true})
+	if err != nil {
+		return nil, err
+	}
+	return sortedRecords(list, (*pb.OptionDetail).GetCode), nil
+}
+
+func sortedRecords[T any](list []T, code func(T) string) []any {
+	sort.Slice(list, func(i, j int) bool {
+		return code(list[i]) < code(list[j])
+	})
+	records := make([]any, 0, len(list))
+	for _, v := range list {
+		records = append(records, v)
+	}
+	return records
+}
+
+// Import reads the whole file before writing, then writes the records in one transaction.
+// Invalid records are reported as issues and skipped, fail policy writes nothing if any conflict.
+func (uc *transferUseCase) Import(
+	ctx context.Context, domain entity.TransferDomain, format entity.TransferFormat,
+	src io.Reader, policy entity.ConflictPolicy,
+) (*entity.TransferResult, error) {
+	switch domain {
+	case entity.TransferDomainUsers, entity.TransferDomainSettings,
+		entity.TransferDomainStocks, entity.TransferDomainFutures, entity.TransferDomainOptions:
+	default:
+		return nil, ErrTransferDomainInvalid
+	}
+	switch policy {
+	case entity.ConflictPolicySkip, entity.ConflictPolicyOverwrite, entity.ConflictPolicyFail:
+	default:
+		return nil, ErrConflictPolicyInvalid
+	}
+	if !transfer.Valid(format) || (domain == entity.TransferDomainSettings && format == entity.TransferFormatProtobuf) {
+		return nil, ErrTransferFormatInvalid
+	}
+	tr, err := transfer.NewReader(src, format)
+	if err != nil {
+		uc.logger.Warnf("Import %s: %v", domain, err)
+		return nil, ErrTransferFileInvalid
+	}
+	if tr.Header().Domain != domain {
+		uc.logger.Warnf("Import %s: file of %s", domain, tr.Header().Domain)
+		return nil, ErrTransferFileInvalid
+	}
+	result := &entity.TransferResult{
+		Domain:    domain,
+		Conflicts: []string{},
+		Issues:    []*entity.ImportIssue{},
+	}
+	switch domain {
+	case entity.TransferDomainUsers:
+		err = uc.importUsers(ctx, tr, policy, result)
+	case entity.TransferDomainSettings:
+		err = uc.importSettings(ctx, tr, policy, result)
+	case entity.TransferDomainStocks:
+		err = uc.importStocks(ctx, tr, policy, result)
+	case entity.TransferDomainFutures:
+		err = uc.importFutures(ctx, tr, policy, result)
+	case entity.TransferDomainOptions:
+		err = uc.importOptions(ctx, tr, policy, result)
+	}
+	if err != nil {
+		if errors.Is(err, transfer.ErrInvalid) {
+			uc.logger.Warnf("Import %s: %v", domain, err)
+			return nil, ErrTransferFileInvalid
+		}
+		if errors.Is(err, ErrTransferConflict) {
+			return result, err
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// readRecords reads all records, the count must match the header or the file is truncated.
+func readRecords[T any](tr *transfer.Reader, newRecord func() T) ([]T, error) {
+	records := []T{}
+	for {
+		record := newRecord()
+		err := tr.Next(record)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	if len(records) != tr.Header().Count {
+		return nil, fmt.Errorf("%w: %d records, header says %d", transfer.ErrInvalid, len(records), tr.Header().Count)
+	}
+	return records, nil
+}
+
+// resolve applies policy on the conflicts found, true if the conflicts should be overwritten.
+func resolve(policy entity.ConflictPolicy, result *entity.TransferResult) (bool, error) {
+	if len(result.Conflicts) == 0 {
+		return false, nil
+	}
+	switch policy {
+	case entity.ConflictPolicyOverwrite:
+		return true, nil
+	case entity.ConflictPolicyFail:
+		return false, ErrTransferConflict
+	default:
+		result.Skipped += len(result.Conflicts)
+		return false, nil
+	}
+}
+
+func (uc *transferUseCase) importUsers(ctx context.Context, tr *transfer.Reader, policy entity.ConflictPolicy, result *entity.TransferResult) error {
+	records, err := readRecords(tr, func() *pb.User { return &pb.User{} })
+	if err != nil {
+		return err
+	}
+	result.Total = len(records)
+	existing, err := uc.userRepo.SelectAllUserCredential(ctx)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]*pb.BasicUser, len(existing))
+	byEmail := make(map[string]string, len(existing))
+	for _, v := range existing {
+		byName[v.GetBasic().GetUsername()] = v.GetBasic()
+		byEmail[v.GetBasic().GetEmail()] = v.GetBasic().GetUsername()
+	}
+
+	inserted := []*pb.User{}
+	conflicts := []*pb.User{}
+	seen := make(map[string]struct{}, len(records))
+	for _, v := range records {
+		b := v.GetBasic()
+		if reason := checkTransferUser(b); reason != "" {
+			result.Issues = append(result.Issues, &entity.ImportIssue{Code: b.GetUsername(), Reason: reason})
+			continue
+		}
+		if _, ok := seen[b.GetUsername()]; ok {
+			result.Issues = append(result.Issues, &entity.ImportIssue{Code: b.GetUsername(), Reason: "duplicate username"})
+			continue
+		}
+		seen[b.GetUsername()] = struct{}{}
+		if owner, ok := byEmail[b.GetEmail()]; ok && owner != b.GetUsername() {
+			result.Issues = append(result.Issues, &entity.ImportIssue{Code: b.GetUsername(), Reason: "email used by " + owner})
+			continue
+		}
+		byEmail[b.GetEmail()] = b.GetUsername()
+		old, ok := byName[b.GetUsername()]
+		switch {
+		case !ok:
+			inserted = append(inserted, &pb.User{Basic: b})
+		case old.GetEmail() == b.GetEmail() && old.GetPassword() == b.GetPassword() && old.GetRole() == b.GetRole():
+			result.Skipped++
+		default:
+			result.Conflicts = append(result.Conflicts, b.GetUsername())
+			conflicts = append(conflicts, &pb.User{Basic: b})
+		}
+	}
+	result.Skipped += len(result.Issues)
+	overwrite, err := resolve(policy, result)
+	if err != nil {
+		return err
+	}
+	if !overwrite {
+		conflicts = nil
+	}
+	if err = uc.userRepo.ImportUsers(ctx, inserted, conflicts); err != nil {
+		return err
+	}
+	result.Inserted = len(inserted)
+	result.Updated = len(conflicts)
+	return nil
+}
+
+// checkTransferUser returns why the user can not be imported, the password must be a bcrypt hash.
+func checkTransferUser(b *pb.BasicUser) string {
+	if b.GetUsername() == "" {
+		return "empty username"
+	}
+	if _, err := mail.ParseAddress(b.GetEmail()); err != nil {
+		return "invalid email"
+	}
+	if b.GetRole() != pb.UserRole_ROOT && b.GetRole() != pb.UserRole_ADMIN && b.GetRole() != pb.UserRole_USER {
+		return "invalid role"
+	}
+	if _, err := bcrypt.Cost([]byte(b.GetPassword())); err != nil {
+		return "password is not a bcrypt hash"
+	}
+	return ""
+}
+
+// importSettings writes the content as stored, then tells the running services by TopicLocalSettingChanged.
+func (uc *transferUseCase) importSettings(ctx context.Context, tr *transfer.Reader, policy entity.ConflictPolicy, result *entity.TransferResult) error {
+	records, err := readRecords(tr, func() *entity.SettingRecord { return &entity.SettingRecord{} })
+	if err != nil {
+		return err
+	}
+	result.Total = len(records)
+	existing, err := uc.systemRepo.SelectAllLocalSetting(ctx)
+	if err != nil {
+		return err
+	}
+
+	inserted := make(map[entity.LocalSettingKey][]byte)
+	conflicts := make(map[entity.LocalSettingKey][]byte)
+	seen := make(map[entity.LocalSettingKey]struct{}, len(records))
+	for _, v := range records {
+		key := entity.LocalSettingKey(v.Key)
+		code := v.Key
+		data := []byte(v.Value)
+		if len(data) == 0 {
+			data = v.Raw
+		}
+		switch {
+		case !transferableSetting(key):
+			result.Issues = append(result.Issues, &entity.ImportIssue{Code: code, Reason: "setting not transferable"})
+			continue
+		case len(data) == 0:
+			result.Issues = append(result.Issues, &entity.ImportIssue{Code: code, Reason: "empty setting"})
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			result.Issues = append(result.Issues, &entity.ImportIssue{Code: code, Reason: "duplicate key"})
+			continue
+		}
+		seen[key] = struct{}{}
+		old, ok := existing[key]
+		switch {
+		case !ok:
+			inserted[key] = data
+		case bytes.Equal(old, data):
+			result.Skipped++
+		default:
+			result.Conflicts = append(result.Conflicts, code)
+			conflicts[key] = data
+		}
+	}
+	result.Skipped += len(result.Issues)
+	overwrite, err := resolve(policy, result)
+	if err != nil {
+		return err
+	}
+	result.Inserted = len(inserted)
+	if overwrite {
+		for k, v := range conflicts {
+			inserted[k] = v
+		}
+		result.Updated = len(conflicts)
+	}
+	if len(inserted) == 0 {
+		return nil
+	}
+	if err = uc.systemRepo.ImportLocalSettings(ctx, inserted); err != nil {
+		return err
+	}
+	for k := range inserted {
+		uc.bus.PublishTopicEvent(TopicLocalSettingChanged, k)
+	}
+	return nil
+}
+
+// splitConflicts returns the rows to write by policy, the rows of existing codes are the conflicts.
+func splitConflicts[T any](
+	rows []T, code func(T) string, existing []string, policy entity.ConflictPolicy, result *entity.TransferResult,
+) ([]T, error) {
+	codes := make(map[string]struct{}, len(existing))
+	for _, v := range existing {
+		codes[v] = struct{}{}
+	}
+	fresh := make([]T, 0, len(rows))
+	conflicts := []T{}
+	for _, v := range rows {
+		if _, ok := codes[code(v)]; ok {
+			result.Conflicts = append(result.Conflicts, code(v))
+			conflicts = append(conflicts, v)
+			continue
+		}
+		fresh = append(fresh, v)
+	}
+	result.Skipped += len(result.Issues)
+	overwrite, err := resolve(policy, result)
+	if err != nil {
+		return nil, err
+	}
+	result.Inserted = len(fresh)
+	if overwrite {
+		result.Updated = len(conflicts)
+		fresh = append(fresh, conflicts...)
+	}
+	return fresh, nil
+}
+
+func (uc *transferUseCase) importStocks(ctx context.Context, tr *transfer.Reader, policy entity.ConflictPolicy, result *entity.TransferResult) error {
+	records, err := readRecords(tr, func() *pb.StockDetail { return &pb.StockDetail{} })
+	if err != nil {
+		return err
+	}
+	result.Total = len(records)
+	rows, issues := stockRows(records)
+	result.Issues = issues
+	existing, err := uc.basicRepo.SelectInstrumentCodes(ctx, entity.InstrumentKindStock)
+	if err != nil {
+		return err
+	}
+	rows, err = splitConflicts(rows, func(v *entity.StockRow) string { return v.Code }, existing, policy, result)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	return uc.basicRepo.MergeStockDetail(ctx, rows)
+}
+
+func (uc *transferUseCase) importFutures(ctx context.Context, tr *transfer.Reader, policy entity.ConflictPolicy, result *entity.TransferResult) error {
+	records, err := readRecords(tr, func() *pb.FutureDetail { return &pb.FutureDetail{} })
+	if err != nil {
+		return err
+	}
+	result.Total = len(records)
+	rows, issues := futureRows(records)
+	result.Issues = issues
+	existing, err := uc.basicRepo.SelectInstrumentCodes(ctx, entity.InstrumentKindFuture)
+	if err != nil {
+		return err
+	}
+	rows, err = splitConflicts(rows, func(v *entity.FutureRow) string { return v.Code }, existing, policy, result)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	return uc.basicRepo.ImportFutureDetail(ctx, rows, nil)
+}
+
+func (uc *transferUseCase) importOptions(ctx context.Context, tr *transfer.Reader, policy entity.ConflictPolicy, result *entity.TransferResult) error {
+	records, err := readRecords(tr, func() *pb.OptionDetail { return &pb.OptionDetail{} })
+	if err != nil {
+		return err
+	}
+	result.Total = len(records)
+	rows, issues := optionRows(records)
+	result.Issues = issues
+	existing, err := uc.basicRepo.SelectInstrumentCodes(ctx, entity.InstrumentKindOption)
+	if err != nil {
+		return err
+	}
+	rows, err = splitConflicts(rows, func(v *entity.OptionRow) string { return v.Code }, existing, policy, result)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	return uc.basicRepo.ImportOptionDetail(ctx, rows, nil)
+}